@@ -16,6 +16,17 @@ func CreateDatabase(cliContext *cli.Context) error {
 		return fmt.Errorf("missing database name")
 	}
 
+	if err := createDatabase(dbName, false); err != nil {
+		return err
+	}
+
+	fmt.Printf("Database '%s' created successfully\n", dbName)
+	return nil
+}
+
+// createDatabase creates a MySQL database with the credentials from the config.
+// With ifNotExists an existing database is not an error.
+func createDatabase(dbName string, ifNotExists bool) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("error loading config: %w", err)
@@ -34,10 +45,12 @@ func CreateDatabase(cliContext *cli.Context) error {
 	}()
 
 	stmt := fmt.Sprintf("CREATE DATABASE `%s`", dbName)
+	if ifNotExists {
+		stmt = fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", dbName)
+	}
 	if _, err := db.Exec(stmt); err != nil {
 		return fmt.Errorf("error creating database '%s': %w", dbName, err)
 	}
 
-	fmt.Printf("Database '%s' created successfully\n", dbName)
 	return nil
 }
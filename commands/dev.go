@@ -0,0 +1,461 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"mo/config"
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	devMinBackoff = time.Second
+	devMaxBackoff = 30 * time.Second
+	// devStableAfter resets the backoff, a process that ran this long didn't crash loop
+	devStableAfter = time.Minute
+	// devStopTimeout is how long processes get to exit after Ctrl-C before they are killed
+	devStopTimeout = 5 * time.Second
+)
+
+// devColors are the ANSI colors of the process prefixes, in order
+var devColors = []string{"36", "33", "35", "32", "34", "31"}
+
+// devProcess is a configured process with its command line split into arguments
+type devProcess struct {
+	Name    string
+	Args    []string
+	Restart bool
+	Color   string
+}
+
+func Dev(cliContext *cli.Context) error {
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return err
+	}
+	runtime, err := utils.ProjectRuntime()
+	if err != nil {
+		return err
+	}
+
+	configured := projectConfig.Dev
+	if len(configured) == 0 {
+		devScript := ""
+		if fileExists("package.json") {
+			if hasDevScript, _ := hasNpmScript("dev"); hasDevScript {
+				manager := detectPackageManager(".")
+				devScript = manager.Name + " " + strings.Join(manager.runArgs("dev"), " ")
+			}
+		}
+		configured = defaultDevProcesses(fileExists("artisan"), devScript, runtime, projectLaravelVersion())
+	}
+
+	processes, err := devProcesses(configured, cliContext.Args().Slice())
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		return fmt.Errorf("nothing to run, configure \"dev\" in %s", config.ProjectConfigFile)
+	}
+	if cliContext.Bool("no-restart") {
+		for i := range processes {
+			processes[i].Restart = false
+		}
+	}
+
+	runner := newDevRunner(os.Stdout, runtime, processes, useDevColors())
+
+	names := make([]string, len(processes))
+	for i, process := range processes {
+		names[i] = process.Name
+	}
+	fmt.Printf("Starting %s (Ctrl-C to stop)\n", strings.Join(names, ", "))
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := runner.start()
+
+	select {
+	case <-done:
+		return nil
+	case <-signals:
+	}
+
+	fmt.Println("\nStopping...")
+	runner.shutdown(os.Interrupt)
+
+	select {
+	case <-done:
+	case <-signals:
+		runner.shutdown(os.Kill)
+		<-done
+	case <-time.After(devStopTimeout):
+		fmt.Println("Processes still running, killing them")
+		runner.shutdown(os.Kill)
+		<-done
+	}
+
+	fmt.Println("✓ All processes stopped")
+	return nil
+}
+
+// defaultDevProcesses are started when .mo.json configures none. Containers
+// already serve the app, so artisan serve only runs on the host. schedule:work
+// exists since Laravel 8 and is left out when the version is unknown. devScript
+// runs the "dev" script of package.json.
+func defaultDevProcesses(isLaravel bool, devScript string, runtime utils.Runtime, version laravelVersion) []config.DevProcess {
+	var processes []config.DevProcess
+
+	if isLaravel {
+		if runtime == utils.RuntimeHost {
+			processes = append(processes, config.DevProcess{Name: "serve", Command: "php artisan serve"})
+		}
+		processes = append(processes, config.DevProcess{Name: "queue", Command: "php artisan queue:work"})
+		if version.atLeast(8) {
+			processes = append(processes, config.DevProcess{Name: "schedule", Command: "php artisan schedule:work"})
+		}
+	}
+	if devScript != "" {
+		processes = append(processes, config.DevProcess{Name: "vite", Command: devScript})
+	}
+
+	return processes
+}
+
+// devProcesses validates the configured processes and keeps the ones named in
+// only, or all of them if only is empty
+func devProcesses(configured []config.DevProcess, only []string) ([]devProcess, error) {
+	var processes []devProcess
+	seen := map[string]bool{}
+
+	for i, entry := range configured {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("dev process %d has no name", i+1)
+		}
+		if seen[entry.Name] {
+			return nil, fmt.Errorf("dev process %q is configured twice", entry.Name)
+		}
+		seen[entry.Name] = true
+
+		args, err := splitCommandLine(entry.Command)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing command of %q: %v", entry.Name, err)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("dev process %q has no command", entry.Name)
+		}
+
+		if len(only) > 0 && !contains(only, entry.Name) {
+			continue
+		}
+		processes = append(processes, devProcess{
+			Name:    entry.Name,
+			Args:    args,
+			Restart: entry.Restart == nil || *entry.Restart,
+			Color:   devColors[len(processes)%len(devColors)],
+		})
+	}
+
+	for _, name := range only {
+		if !seen[name] {
+			return nil, fmt.Errorf("unknown dev process %q", name)
+		}
+	}
+
+	return processes, nil
+}
+
+// splitCommandLine splits a command into arguments on whitespace, keeping
+// single or double quoted parts together
+func splitCommandLine(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// nextBackoff doubles the restart delay up to devMaxBackoff
+func nextBackoff(current time.Duration) time.Duration {
+	if next := current * 2; next < devMaxBackoff {
+		return next
+	}
+	return devMaxBackoff
+}
+
+// useDevColors honours NO_COLOR and skips colors when stdout isn't a terminal
+func useDevColors() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// devRunner starts the processes, restarts them when they crash and stops
+// them together
+type devRunner struct {
+	out       io.Writer
+	runtime   utils.Runtime
+	processes []devProcess
+	width     int
+	color     bool
+
+	mu       sync.Mutex // guards out, running and stopping
+	running  map[string]*exec.Cmd
+	stopping bool
+	stop     chan struct{}
+}
+
+func newDevRunner(out io.Writer, runtime utils.Runtime, processes []devProcess, color bool) *devRunner {
+	width := 0
+	for _, process := range processes {
+		if len(process.Name) > width {
+			width = len(process.Name)
+		}
+	}
+
+	return &devRunner{
+		out:       out,
+		runtime:   runtime,
+		processes: processes,
+		width:     width,
+		color:     color,
+		running:   map[string]*exec.Cmd{},
+		stop:      make(chan struct{}),
+	}
+}
+
+// start supervises every process and returns a channel that is closed once
+// all of them have exited for good
+func (r *devRunner) start() <-chan struct{} {
+	var wg sync.WaitGroup
+	for _, process := range r.processes {
+		wg.Add(1)
+		go func(process devProcess) {
+			defer wg.Done()
+			r.supervise(process)
+		}(process)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// shutdown stops restarts and sends sig to every running process group. In a
+// container that only reaches the exec client, which doesn't pass signals on,
+// so the process inside the container is stopped with pkill as well.
+func (r *devRunner) shutdown(sig os.Signal) {
+	r.mu.Lock()
+	if !r.stopping {
+		r.stopping = true
+		close(r.stop)
+	}
+	var inContainer []devProcess
+	for _, process := range r.processes {
+		cmd, ok := r.running[process.Name]
+		if !ok {
+			continue
+		}
+		if name, _ := utils.ContainerCommand(r.runtime, nil, false, process.Args[0]); name != process.Args[0] {
+			inContainer = append(inContainer, process)
+		}
+		utils.SignalProcessGroup(cmd, sig)
+	}
+	r.mu.Unlock()
+
+	for _, process := range inContainer {
+		if err := r.stopInContainer(process, sig); err != nil {
+			r.status(process, fmt.Sprintf("could not stop it in the container: %v", err))
+		}
+	}
+}
+
+// stopInContainer signals the process running in the app container. queue:work
+// finishes its current job on SIGTERM.
+func (r *devRunner) stopInContainer(process devProcess, sig os.Signal) error {
+	signal := "-TERM"
+	if sig == os.Kill {
+		signal = "-KILL"
+	}
+	name, args := utils.AppContainerCommand(r.runtime, "pkill", signal, "-f", containerProcessPattern(process.Args))
+	err := exec.Command(name, args...).Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		// Nothing matched, the process already exited
+		return nil
+	}
+	return err
+}
+
+// containerProcessPattern matches the command line of the process for pkill -f.
+// It is anchored so it doesn't match the shell that runs pkill.
+func containerProcessPattern(args []string) string {
+	return "^" + regexp.QuoteMeta(strings.Join(args, " "))
+}
+
+func (r *devRunner) isStopping() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopping
+}
+
+func (r *devRunner) supervise(process devProcess) {
+	backoff := devMinBackoff
+
+	for {
+		started := time.Now()
+		err := r.run(process)
+		if r.isStopping() {
+			return
+		}
+
+		status := "exited"
+		if err != nil {
+			status = fmt.Sprintf("exited (%v)", err)
+		}
+		if !process.Restart {
+			r.status(process, status)
+			return
+		}
+
+		if time.Since(started) >= devStableAfter {
+			backoff = devMinBackoff
+		}
+		r.status(process, fmt.Sprintf("%s, restarting in %s", status, backoff))
+
+		select {
+		case <-r.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// run starts the process in its own process group and waits for it to exit.
+// Its output goes through the prefix writer, so it gets no terminal.
+func (r *devRunner) run(process devProcess) error {
+	name, args := utils.ContainerCommand(r.runtime, nil, false, process.Args[0], process.Args[1:]...)
+	cmd := utils.ProcessGroupCommand(name, args...)
+	writer := &prefixWriter{out: r.out, mu: &r.mu, prefix: r.prefix(process)}
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	r.mu.Lock()
+	if r.stopping {
+		r.mu.Unlock()
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	r.running[process.Name] = cmd
+	r.mu.Unlock()
+
+	err := cmd.Wait()
+	writer.Flush()
+
+	r.mu.Lock()
+	delete(r.running, process.Name)
+	r.mu.Unlock()
+	return err
+}
+
+func (r *devRunner) prefix(process devProcess) string {
+	label := fmt.Sprintf("%-*s |", r.width, process.Name)
+	if r.color {
+		label = "\033[" + process.Color + "m" + label + "\033[0m"
+	}
+	return label + " "
+}
+
+// status prints a line about the process itself, not from its output
+func (r *devRunner) status(process devProcess, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, "%s%s\n", r.prefix(process), message)
+}
+
+// prefixWriter writes complete lines to out, each with the prefix. mu is shared
+// by all processes so their lines don't interleave.
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, bytes.TrimSuffix(w.buf[:i], []byte("\r")))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a last line that didn't end with a newline
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+		w.buf = nil
+	}
+}
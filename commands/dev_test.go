@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mo/config"
+	"mo/utils"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "php artisan serve", want: []string{"php", "artisan", "serve"}},
+		{command: "  npm  run\tdev ", want: []string{"npm", "run", "dev"}},
+		{command: `stripe listen --forward-to "localhost:8000/stripe webhook"`, want: []string{"stripe", "listen", "--forward-to", "localhost:8000/stripe webhook"}},
+		{command: `sh -c 'echo "hi"'`, want: []string{"sh", "-c", `echo "hi"`}},
+		{command: `echo ""`, want: []string{"echo", ""}},
+		{command: "", want: nil},
+		{command: `echo "open`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := splitCommandLine(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	backoff := devMinBackoff
+	var got []time.Duration
+	for i := 0; i < 7; i++ {
+		got = append(got, backoff)
+		backoff = nextBackoff(backoff)
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("backoff %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDefaultDevProcesses(t *testing.T) {
+	laravel7 := laravelVersion{Major: 7, Minor: 30, Version: "7.30.6"}
+	laravel11 := laravelVersion{Major: 11, Minor: 9, Version: "11.9.2"}
+
+	tests := []struct {
+		name      string
+		isLaravel bool
+		devScript string
+		runtime   utils.Runtime
+		version   laravelVersion
+		want      []string
+	}{
+		{name: "laravel on host", isLaravel: true, devScript: "npm run dev", runtime: utils.RuntimeHost, version: laravel11, want: []string{"serve", "queue", "schedule", "vite"}},
+		{name: "laravel in sail", isLaravel: true, devScript: "npm run dev", runtime: utils.RuntimeSail, version: laravel11, want: []string{"queue", "schedule", "vite"}},
+		{name: "laravel 7", isLaravel: true, runtime: utils.RuntimeHost, version: laravel7, want: []string{"serve", "queue"}},
+		{name: "unknown version", isLaravel: true, runtime: utils.RuntimeHost, want: []string{"serve", "queue"}},
+		{name: "node only", devScript: "npm run dev", runtime: utils.RuntimeHost, want: []string{"vite"}},
+		{name: "nothing", runtime: utils.RuntimeHost, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, process := range defaultDevProcesses(tt.isLaravel, tt.devScript, tt.runtime, tt.version) {
+				names = append(names, process.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevProcesses(t *testing.T) {
+	noRestart := false
+	configured := []config.DevProcess{
+		{Name: "serve", Command: "php artisan serve"},
+		{Name: "stripe", Command: "stripe listen", Restart: &noRestart},
+		{Name: "vite", Command: "npm run dev"},
+	}
+
+	processes, err := devProcesses(configured, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 3 {
+		t.Fatalf("got %d processes, want 3", len(processes))
+	}
+	if !processes[0].Restart || processes[1].Restart {
+		t.Errorf("restart = %v, %v, want true, false", processes[0].Restart, processes[1].Restart)
+	}
+	if processes[0].Color == processes[1].Color {
+		t.Error("expected different colors per process")
+	}
+
+	processes, err = devProcesses(configured, []string{"vite", "serve"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 2 || processes[0].Name != "serve" || processes[1].Name != "vite" {
+		t.Errorf("got %+v, want serve and vite in config order", processes)
+	}
+
+	errorCases := map[string]struct {
+		configured []config.DevProcess
+		only       []string
+	}{
+		"unknown name":   {configured: configured, only: []string{"horizon"}},
+		"missing name":   {configured: []config.DevProcess{{Command: "php artisan serve"}}},
+		"empty command":  {configured: []config.DevProcess{{Name: "serve"}}},
+		"duplicate name": {configured: []config.DevProcess{{Name: "a", Command: "x"}, {Name: "a", Command: "y"}}},
+		"bad quoting":    {configured: []config.DevProcess{{Name: "a", Command: `echo "x`}}},
+	}
+	for name, tc := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := devProcesses(tc.configured, tc.only); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestContainerProcessPattern(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"queue": {args: []string{"php", "artisan", "queue:work", "--queue=high,default"}, want: "^php artisan queue:work --queue=high,default"},
+		"regex": {args: []string{"php", "artisan", "horizon", "--tries=3.5"}, want: `^php artisan horizon --tries=3\.5`},
+		"npm":   {args: []string{"npm", "run", "dev"}, want: "^npm run dev"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := containerProcessPattern(tt.args); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := &prefixWriter{out: &out, mu: &sync.Mutex{}, prefix: "vite | "}
+
+	writer.Write([]byte("first\r\nsec"))
+	writer.Write([]byte("ond\nno newline"))
+	writer.Flush()
+
+	want := "vite | first\nvite | second\nvite | no newline\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestDevRunnerRestartsAndStops(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	processes, err := devProcesses([]config.DevProcess{
+		{Name: "once", Command: "sh -c 'echo done'", Restart: new(bool)},
+		{Name: "crash", Command: "sh -c 'echo crashing; exit 3'"},
+		{Name: "worker", Command: "sh -c 'echo started; sleep 30'"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	runner := newDevRunner(&out, utils.RuntimeHost, processes, false)
+	done := runner.start()
+
+	// Wait for the worker to run and the first crash to be reported, then stop everything
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		output := runnerOutput(runner, &out)
+		if strings.Contains(output, "restarting in 1s") && strings.Contains(output, "worker | started") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("processes did not start, output:\n%s", output)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	runner.shutdown(os.Interrupt)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("processes did not stop")
+	}
+
+	output := runnerOutput(runner, &out)
+	for _, want := range []string{
+		"once   | done\n",
+		"once   | exited\n",
+		"crash  | crashing\n",
+		"crash  | exited (exit status 3), restarting in 1s\n",
+		"worker | started\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output is missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// runnerOutput reads what the runner wrote so far, its writers hold r.mu
+func runnerOutput(r *devRunner, out *bytes.Buffer) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return out.String()
+}
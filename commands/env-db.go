@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mo/config"
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// dbConnectionKeys are the .env keys that belong to a server based connection
+var dbConnectionKeys = []string{"DB_HOST", "DB_PORT", "DB_DATABASE", "DB_USERNAME", "DB_PASSWORD"}
+
+const sqliteDatabasePath = "database/database.sqlite"
+
+func EnvDB(cliContext *cli.Context) error {
+	driver := cliContext.Args().First()
+	if driver == "" {
+		return fmt.Errorf("usage: env:db <sqlite|mysql|pgsql>")
+	}
+	return switchDatabaseDriver(utils.NewEnvManager(".env"), driver)
+}
+
+func switchDatabaseDriver(envManager *utils.EnvManager, driver string) error {
+	if driver != "sqlite" && driver != "mysql" && driver != "pgsql" {
+		return fmt.Errorf("unsupported driver '%s' (use sqlite, mysql or pgsql)", driver)
+	}
+
+	if !fileExists(envManager.Path) {
+		return fmt.Errorf("%s file not found", envManager.Path)
+	}
+
+	current, found, err := envManager.GetVar("DB_CONNECTION")
+	if err != nil {
+		return fmt.Errorf("error reading DB_CONNECTION: %v", err)
+	}
+	if !found || current == "" {
+		current = "mysql"
+	}
+	current = utils.UnquoteValue(current)
+
+	if current == driver {
+		fmt.Printf("DB_CONNECTION is already set to %s.\n", driver)
+		return nil
+	}
+
+	saved, err := loadSavedConnections()
+	if err != nil {
+		return err
+	}
+
+	// Remember the connection we are leaving so switching back restores it exactly
+	if current != "sqlite" {
+		values := map[string]string{}
+		for _, key := range dbConnectionKeys {
+			value, found, err := envManager.GetVar(key)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %v", key, err)
+			}
+			if found {
+				values[key] = value
+			}
+		}
+		saved[current] = values
+		if err := storeSavedConnections(saved); err != nil {
+			return err
+		}
+	}
+
+	if err := envManager.SetVar("DB_CONNECTION", driver); err != nil {
+		return fmt.Errorf("error setting DB_CONNECTION: %v", err)
+	}
+
+	if driver == "sqlite" {
+		for _, key := range dbConnectionKeys {
+			if err := envManager.CommentVar(key); err != nil {
+				return fmt.Errorf("error commenting out %s: %v", key, err)
+			}
+		}
+		if err := ensureSqliteDatabase(); err != nil {
+			return err
+		}
+		fmt.Printf("DB_CONNECTION set to sqlite (%s).\n", sqliteDatabasePath)
+		return nil
+	}
+
+	values, restored := saved[driver]
+	if !restored {
+		values, err = defaultConnectionValues(envManager, driver)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range dbConnectionKeys {
+		value, ok := values[key]
+		if !ok {
+			if err := envManager.CommentVar(key); err != nil {
+				return fmt.Errorf("error commenting out %s: %v", key, err)
+			}
+			continue
+		}
+		if err := envManager.SetVar(key, value); err != nil {
+			return fmt.Errorf("error setting %s: %v", key, err)
+		}
+	}
+
+	if restored {
+		fmt.Printf("DB_CONNECTION set to %s, previous connection settings restored.\n", driver)
+	} else {
+		fmt.Printf("DB_CONNECTION set to %s.\n", driver)
+	}
+	return nil
+}
+
+// defaultConnectionValues uncomments existing values where possible and falls back to local defaults
+func defaultConnectionValues(envManager *utils.EnvManager, driver string) (map[string]string, error) {
+	defaults := map[string]string{
+		"DB_HOST":     "127.0.0.1",
+		"DB_PORT":     "3306",
+		"DB_DATABASE": projectDatabaseName(),
+		"DB_USERNAME": "root",
+		"DB_PASSWORD": "",
+	}
+	if driver == "pgsql" {
+		defaults["DB_PORT"] = "5432"
+		defaults["DB_USERNAME"] = "postgres"
+	}
+
+	values := map[string]string{}
+	for _, key := range dbConnectionKeys {
+		value, found, err := envManager.GetCommentedVar(key)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", key, err)
+		}
+		// Commented values usually are Laravel's MySQL defaults, so keep pgsql's own port and user
+		if found && (driver == "mysql" || (key != "DB_PORT" && key != "DB_USERNAME")) {
+			values[key] = value
+		} else {
+			values[key] = defaults[key]
+		}
+	}
+	return values, nil
+}
+
+func projectDatabaseName() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "laravel"
+	}
+	return filepath.Base(cwd)
+}
+
+func ensureSqliteDatabase() error {
+	if fileExists(sqliteDatabasePath) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(sqliteDatabasePath), 0755); err != nil {
+		return fmt.Errorf("error creating database directory: %v", err)
+	}
+	if err := os.WriteFile(sqliteDatabasePath, []byte{}, 0644); err != nil {
+		return fmt.Errorf("error creating %s: %v", sqliteDatabasePath, err)
+	}
+	fmt.Printf("Created %s\n", sqliteDatabasePath)
+	return nil
+}
+
+func savedConnectionsPath() (string, error) {
+	dir, err := config.ProjectDataDir(".")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "db-connections.json"), nil
+}
+
+func loadSavedConnections() (map[string]map[string]string, error) {
+	saved := map[string]map[string]string{}
+
+	path, err := savedConnectionsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading saved connections: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("error parsing saved connections: %v", err)
+	}
+	return saved, nil
+}
+
+func storeSavedConnections(saved map[string]map[string]string) error {
+	path, err := savedConnectionsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("error creating project data directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"mo/utils"
+)
+
+func TestSwitchDatabaseDriverRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(oldWd)
+	t.Setenv("HOME", dir)
+
+	original := "APP_NAME=Shop\n\n# Database\nDB_CONNECTION=mysql\nDB_HOST=mysql\nDB_PORT=3307\nDB_DATABASE=shop\nDB_USERNAME=sail\nDB_PASSWORD=\"p@ss word\"\n\nMAIL_MAILER=log\n"
+	if err := os.WriteFile(".env", []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	envManager := utils.NewEnvManager(".env")
+
+	if err := switchDatabaseDriver(envManager, "sqlite"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "DB_CONNECTION=sqlite\n") {
+		t.Errorf("DB_CONNECTION not switched to sqlite:\n%s", content)
+	}
+	for _, key := range dbConnectionKeys {
+		if value, found, _ := envManager.GetVar(key); found {
+			t.Errorf("%s=%s is still active with sqlite", key, value)
+		}
+	}
+	if !fileExists(sqliteDatabasePath) {
+		t.Errorf("%s was not created", sqliteDatabasePath)
+	}
+
+	if err := switchDatabaseDriver(envManager, "mysql"); err != nil {
+		t.Fatal(err)
+	}
+	content, err = os.ReadFile(".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != original {
+		t.Errorf("switching back did not restore .env:\ngot  %q\nwant %q", content, original)
+	}
+}
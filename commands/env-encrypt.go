@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+func EnvEncrypt(cliContext *cli.Context) error {
+	envManager := utils.NewEnvManager(utils.EnvFilePath(cliContext.String("env")))
+	encryptedPath := envManager.Path + ".encrypted"
+	cipherName := cliContext.String("cipher")
+
+	if !fileExists(envManager.Path) {
+		return fmt.Errorf("environment file %s not found", envManager.Path)
+	}
+	if fileExists(encryptedPath) && !cliContext.Bool("force") {
+		return fmt.Errorf("encrypted environment file %s already exists, use --force to overwrite", encryptedPath)
+	}
+
+	key := cliContext.String("key")
+	if key == "" {
+		generated, err := utils.GenerateKey(cipherName)
+		if err != nil {
+			return err
+		}
+		key = generated
+	}
+
+	rawKey, err := utils.ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := os.ReadFile(envManager.Path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", envManager.Path, err)
+	}
+
+	encrypted, err := utils.EncryptEnv(plaintext, rawKey, cipherName)
+	if err != nil {
+		return fmt.Errorf("error encrypting %s: %w", envManager.Path, err)
+	}
+
+	if err := os.WriteFile(encryptedPath, encrypted, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", encryptedPath, err)
+	}
+
+	fmt.Printf("✓ Environment successfully encrypted to %s\n", encryptedPath)
+	fmt.Printf("  Key:    %s\n", key)
+	fmt.Printf("  Cipher: %s\n", cipherName)
+	return nil
+}
+
+func EnvDecrypt(cliContext *cli.Context) error {
+	envManager := utils.NewEnvManager(utils.EnvFilePath(cliContext.String("env")))
+	encryptedPath := envManager.Path + ".encrypted"
+	cipherName := cliContext.String("cipher")
+
+	outputPath := envManager.Path
+	if cliContext.IsSet("filename") {
+		outputPath = cliContext.String("filename")
+	}
+
+	key := cliContext.String("key")
+	if key == "" {
+		key = os.Getenv("LARAVEL_ENV_ENCRYPTION_KEY")
+	}
+	if key == "" {
+		return fmt.Errorf("a decryption key is required (use --key or LARAVEL_ENV_ENCRYPTION_KEY)")
+	}
+
+	if !fileExists(encryptedPath) {
+		return fmt.Errorf("encrypted environment file %s not found", encryptedPath)
+	}
+	if fileExists(outputPath) && !cliContext.Bool("force") {
+		return fmt.Errorf("environment file %s already exists, use --force to overwrite", outputPath)
+	}
+
+	rawKey, err := utils.ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", encryptedPath, err)
+	}
+
+	plaintext, err := utils.DecryptEnv(encrypted, rawKey, cipherName)
+	if err != nil {
+		return fmt.Errorf("error decrypting %s: %w", encryptedPath, err)
+	}
+
+	if err := os.WriteFile(outputPath, plaintext, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", outputPath, err)
+	}
+
+	fmt.Printf("✓ Environment successfully decrypted to %s\n", outputPath)
+	return nil
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// secretKeyPatterns are key names whose values are always treated as secrets
+var secretKeyPatterns = []string{"*_KEY", "*_SECRET", "*_PASSWORD", "*_TOKEN"}
+
+// secretValuePrefixes are well known prefixes of API keys and tokens
+var secretValuePrefixes = []string{"base64:", "sk_", "pk_live_", "rk_", "ghp_", "gho_", "xox", "AKIA"}
+
+// envLinePattern matches assignments, including commented out ones like
+// "# DB_PASSWORD=secret" which would leak just the same
+var envLinePattern = regexp.MustCompile(`^(\s*(?:#\s*)?(?:export\s+)?[A-Za-z_][A-Za-z0-9_.]*\s*=)(.*)$`)
+
+var safeValuePattern = regexp.MustCompile(`^(true|false|null|empty|[0-9]+(\.[0-9]+)?|[a-z][a-z0-9_-]{0,15})$`)
+
+var safeHosts = []string{"127.0.0.1", "localhost", "0.0.0.0", "::1", "mysql", "redis", "mailpit", "meilisearch", "pgsql", "memcached"}
+
+func EnvExample(cliContext *cli.Context) error {
+	source := cliContext.String("source")
+	output := cliContext.String("output")
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", source, err)
+	}
+
+	patterns := append(append([]string{}, secretKeyPatterns...), cliContext.StringSlice("secret")...)
+	sanitized, replaced := sanitizeEnvContent(string(content), patterns, cliContext.String("placeholder"))
+
+	if cliContext.Bool("stdout") {
+		fmt.Print(sanitized)
+		return nil
+	}
+
+	if fileExists(output) && !cliContext.Bool("force") {
+		return fmt.Errorf("%s already exists, use --force to overwrite it (or --stdout to preview)", output)
+	}
+
+	if err := os.WriteFile(output, []byte(sanitized), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", output, err)
+	}
+
+	fmt.Printf("✓ %s generated from %s\n", output, source)
+	if len(replaced) > 0 {
+		fmt.Printf("Replaced %d secret value(s):\n", len(replaced))
+		for _, key := range replaced {
+			fmt.Printf("  %s\n", key)
+		}
+	}
+	return nil
+}
+
+// sanitizeEnvContent keeps comments, blank lines and key order, and replaces
+// secret looking values with the placeholder. It returns the keys it replaced.
+func sanitizeEnvContent(content string, patterns []string, placeholder string) (string, []string) {
+	lines := strings.Split(content, "\n")
+	var replaced []string
+
+	for i, line := range lines {
+		prefix, key, value, ok := splitEnvAssignment(line)
+		if !ok {
+			continue
+		}
+
+		if looksLikeSecret(key, value, patterns) {
+			lines[i] = prefix + strings.ReplaceAll(placeholder, "{key}", key)
+			replaced = append(replaced, key)
+		}
+	}
+
+	return strings.Join(lines, "\n"), replaced
+}
+
+// splitEnvAssignment splits an assignment, commented out or not, into the part
+// up to and including '=', the key and the trimmed value
+func splitEnvAssignment(line string) (string, string, string, bool) {
+	match := envLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", "", "", false
+	}
+
+	key := strings.TrimSpace(strings.TrimSuffix(match[1], "="))
+	key = strings.TrimSpace(strings.TrimLeft(key, "#"))
+	key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+	return match[1], key, strings.TrimSpace(match[2]), true
+}
+
+// looksLikeSecret decides whether a value should stay out of a committed env file,
+// based on the key name, known token prefixes, embedded credentials and entropy
+func looksLikeSecret(key, rawValue string, patterns []string) bool {
+	value := utils.UnquoteValue(rawValue)
+	if value == "" || strings.Contains(value, "${") {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	if isSafeEnvValue(value) {
+		return false
+	}
+
+	for _, prefix := range secretValuePrefixes {
+		if strings.HasPrefix(value, prefix) && len(value) > len(prefix)+8 {
+			return true
+		}
+	}
+
+	if parsed, err := url.Parse(value); err == nil && parsed.User != nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			return true
+		}
+	}
+
+	return isHighEntropy(value)
+}
+
+func isSafeEnvValue(value string) bool {
+	if safeValuePattern.MatchString(value) {
+		return true
+	}
+	for _, host := range safeHosts {
+		if value == host {
+			return true
+		}
+	}
+	return strings.HasSuffix(value, ".test") || strings.HasSuffix(value, ".local") || strings.HasPrefix(value, "http://localhost")
+}
+
+// isHighEntropy reports random looking strings such as API keys. Words, URLs and
+// sentences have a much lower character entropy than generated tokens.
+func isHighEntropy(value string) bool {
+	if len(value) < 20 || strings.ContainsAny(value, " /") {
+		return false
+	}
+
+	classes := 0
+	for _, set := range []string{"abcdefghijklmnopqrstuvwxyz", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "0123456789"} {
+		if strings.ContainsAny(value, set) {
+			classes++
+		}
+	}
+	if classes < 2 {
+		return false
+	}
+
+	return shannonEntropy(value) >= 3.5
+}
+
+func shannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+	for _, r := range value {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
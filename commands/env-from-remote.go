@@ -0,0 +1,300 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"mo/config"
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// defaultRemoteEnvSettings are applied to every downloaded .env, a profile's
+// "set" rules take precedence
+var defaultRemoteEnvSettings = map[string]string{
+	"APP_ENV":   "local",
+	"APP_DEBUG": "true",
+	"DB_HOST":   "127.0.0.1",
+}
+
+// localConnectionKeys are kept from the local .env so pull/push keep working
+var localConnectionKeys = []string{
+	"PULL_SSH_USER", "PULL_HOST", "PULL_PROJECT_DIR",
+	"PUSH_SSH_USER", "PUSH_HOST", "PUSH_PROJECT_DIR",
+}
+
+// envRewriteResult summarizes what rewriteEnvContent changed
+type envRewriteResult struct {
+	Content string
+	Set     []envRewriteChange
+	Dropped []string
+}
+
+type envRewriteChange struct {
+	Key      string
+	OldValue string
+	NewValue string
+	Added    bool
+}
+
+func EnvFromRemote(cliContext *cli.Context) error {
+	output := cliContext.String("output")
+	profileName := cliContext.Args().First()
+
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return err
+	}
+
+	profile, err := envRemoteProfile(projectConfig, profileName)
+	if err != nil {
+		return err
+	}
+
+	user, host, projectDir, err := envRemoteConnection(profile)
+	if err != nil {
+		return err
+	}
+
+	set, err := envRemoteSettings(profile, output)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp("", "mo-remote-env-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	remotePath := fmt.Sprintf("%s@%s:%s", user, host, strings.TrimSuffix(projectDir, "/")+"/.env")
+	fmt.Printf("Downloading %s...\n", remotePath)
+	if err := utils.RunCommand("scp", "-q", remotePath, tmpFile.Name()); err != nil {
+		return fmt.Errorf("error downloading remote .env: %v", err)
+	}
+
+	remoteContent, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return fmt.Errorf("error reading downloaded .env: %v", err)
+	}
+
+	result := rewriteEnvContent(string(remoteContent), set, profile.Drop)
+
+	fmt.Println("----------------------------")
+	printEnvRewriteSummary(result)
+	fmt.Println("----------------------------")
+
+	if cliContext.Bool("dry-run") {
+		fmt.Printf("Dry run, %s was not written.\n", output)
+		return nil
+	}
+
+	if fileExists(output) {
+		backup := output + ".backup"
+		if err := copyFile(output, backup); err != nil {
+			return fmt.Errorf("error backing up %s: %v", output, err)
+		}
+		fmt.Printf("✓ Previous %s saved as %s\n", output, backup)
+	}
+
+	if err := os.WriteFile(output, []byte(result.Content), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", output, err)
+	}
+
+	fmt.Printf("✓ %s written from %s\n", output, host)
+	return nil
+}
+
+// envRemoteProfile looks up the named profile in .mo.json. Without a name the
+// "default" profile is used if there is one, otherwise only the built-in rules apply.
+func envRemoteProfile(projectConfig *config.ProjectConfig, name string) (config.EnvRemoteProfile, error) {
+	if name == "" {
+		return projectConfig.EnvFromRemote["default"], nil
+	}
+
+	profile, ok := projectConfig.EnvFromRemote[name]
+	if !ok {
+		var names []string
+		for profileName := range projectConfig.EnvFromRemote {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return profile, fmt.Errorf("no env_from_remote profiles configured in %s", config.ProjectConfigFile)
+		}
+		return profile, fmt.Errorf("unknown profile %q, available: %s", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// envRemoteConnection returns the SSH user, host and project directory of the
+// profile, falling back to the PULL_* settings of the local .env
+func envRemoteConnection(profile config.EnvRemoteProfile) (string, string, string, error) {
+	if profile.SSHUser != "" && profile.Host != "" && profile.ProjectDir != "" {
+		return profile.SSHUser, profile.Host, profile.ProjectDir, nil
+	}
+
+	if !fileExists(".env") {
+		return "", "", "", fmt.Errorf("no .env to read PULL_* from, set ssh_user, host and project_dir for the profile in %s", config.ProjectConfigFile)
+	}
+
+	pullEnv, err := utils.EnsureRequiredEnvVars("pull")
+	if err != nil {
+		return "", "", "", err
+	}
+
+	user, host, projectDir := profile.SSHUser, profile.Host, profile.ProjectDir
+	if user == "" {
+		user = utils.UnquoteValue(pullEnv["PULL_SSH_USER"])
+	}
+	if host == "" {
+		host = utils.UnquoteValue(pullEnv["PULL_HOST"])
+	}
+	if projectDir == "" {
+		projectDir = utils.UnquoteValue(pullEnv["PULL_PROJECT_DIR"])
+	}
+	return user, host, projectDir, nil
+}
+
+// envRemoteSettings merges the values to set in order of precedence: local
+// connection keys, built-in defaults, presets and the profile's own rules
+func envRemoteSettings(profile config.EnvRemoteProfile, envPath string) (map[string]string, error) {
+	set := map[string]string{}
+
+	if fileExists(envPath) {
+		envManager := utils.NewEnvManager(envPath)
+		for _, key := range localConnectionKeys {
+			value, found, err := envManager.GetVar(key)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %v", envPath, err)
+			}
+			if found {
+				set[key] = value
+			}
+		}
+	}
+
+	for key, value := range defaultRemoteEnvSettings {
+		set[key] = value
+	}
+
+	for _, preset := range profile.Presets {
+		switch preset {
+		case "maildev":
+			for _, setting := range mailDevSettings {
+				set[setting[0]] = setting[1]
+			}
+		case "mailtrap":
+			settings, err := mailtrapSettings()
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range settings {
+				set[key] = value
+			}
+		case "sqlite":
+			set["DB_CONNECTION"] = "sqlite"
+		default:
+			return nil, fmt.Errorf("unknown preset %q (supported: maildev, mailtrap, sqlite)", preset)
+		}
+	}
+
+	for key, value := range profile.Set {
+		set[key] = value
+	}
+
+	return set, nil
+}
+
+// rewriteEnvContent drops keys matching the drop patterns, overwrites the keys in
+// set and appends the ones the remote file doesn't have. Comments and order are kept.
+// Every copy of a duplicated key is rewritten, phpdotenv uses the last one.
+func rewriteEnvContent(content string, set map[string]string, drop []string) envRewriteResult {
+	var result envRewriteResult
+	seen := map[string]bool{}
+	changed := map[string]bool{}
+	var lines []string
+
+	for _, line := range strings.Split(content, "\n") {
+		prefix, key, value, ok := splitEnvAssignment(line)
+		if !ok || strings.HasPrefix(strings.TrimSpace(prefix), "#") {
+			lines = append(lines, line)
+			continue
+		}
+
+		if isIgnoredEnvKey(key, drop) {
+			if !seen[key] {
+				result.Dropped = append(result.Dropped, key)
+			}
+			seen[key] = true
+			continue
+		}
+
+		if newValue, ok := set[key]; ok && newValue != value {
+			if !changed[key] {
+				result.Set = append(result.Set, envRewriteChange{Key: key, OldValue: value, NewValue: newValue})
+				changed[key] = true
+			}
+			line = fmt.Sprintf("%s=%s", key, newValue)
+		}
+		seen[key] = true
+		lines = append(lines, line)
+	}
+
+	var missing []string
+	for key := range set {
+		if !seen[key] && !isIgnoredEnvKey(key, drop) {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 {
+		// Append before the trailing newline, if any
+		trailing := len(lines) > 0 && lines[len(lines)-1] == ""
+		if trailing {
+			lines = lines[:len(lines)-1]
+		}
+		for _, key := range missing {
+			lines = append(lines, fmt.Sprintf("%s=%s", key, set[key]))
+			result.Set = append(result.Set, envRewriteChange{Key: key, NewValue: set[key], Added: true})
+		}
+		if trailing {
+			lines = append(lines, "")
+		}
+	}
+
+	result.Content = strings.Join(lines, "\n")
+	return result
+}
+
+func printEnvRewriteSummary(result envRewriteResult) {
+	if len(result.Set) == 0 && len(result.Dropped) == 0 {
+		fmt.Println("No values were rewritten.")
+		return
+	}
+
+	if len(result.Set) > 0 {
+		fmt.Println("Rewritten:")
+		for _, change := range result.Set {
+			newValue := displayHistoryValue(change.Key, change.NewValue, false)
+			if change.Added {
+				fmt.Printf("  + %s=%s\n", change.Key, newValue)
+				continue
+			}
+			oldValue := displayHistoryValue(change.Key, change.OldValue, false)
+			fmt.Printf("  ~ %s: %s → %s\n", change.Key, oldValue, newValue)
+		}
+	}
+
+	if len(result.Dropped) > 0 {
+		fmt.Println("Dropped:")
+		for _, key := range result.Dropped {
+			fmt.Printf("  - %s\n", key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestRewriteEnvContent(t *testing.T) {
+	content := `APP_NAME=Shop
+APP_ENV=staging
+APP_DEBUG=false
+
+# Database
+DB_HOST=10.0.0.5
+DB_DATABASE=shop
+
+SENTRY_LARAVEL_DSN=https://key@sentry.io/1
+SENTRY_TRACES_SAMPLE_RATE=0.2
+`
+
+	set := map[string]string{
+		"APP_ENV":     "local",
+		"APP_DEBUG":   "true",
+		"DB_HOST":     "127.0.0.1",
+		"DB_DATABASE": "shop",
+		"MAIL_HOST":   "127.0.0.1",
+		"SENTRY_DSN":  "dropped-anyway",
+	}
+
+	result := rewriteEnvContent(content, set, []string{"SENTRY_*"})
+
+	want := `APP_NAME=Shop
+APP_ENV=local
+APP_DEBUG=true
+
+# Database
+DB_HOST=127.0.0.1
+DB_DATABASE=shop
+
+MAIL_HOST=127.0.0.1
+`
+	if result.Content != want {
+		t.Errorf("content = %q, want %q", result.Content, want)
+	}
+
+	if len(result.Dropped) != 2 {
+		t.Errorf("dropped = %v, want the two SENTRY_* keys", result.Dropped)
+	}
+
+	// DB_DATABASE already has the value and is not reported
+	if len(result.Set) != 4 {
+		t.Fatalf("set = %+v, want 4 changes", result.Set)
+	}
+	if last := result.Set[3]; last.Key != "MAIL_HOST" || !last.Added {
+		t.Errorf("last change = %+v, want MAIL_HOST to be added", last)
+	}
+}
+
+func TestRewriteEnvContentDuplicates(t *testing.T) {
+	content := "DB_HOST=10.0.0.5\nDB_PORT=3306\n# DB_HOST=10.0.0.6\nDB_HOST=prod-db.internal\n"
+
+	result := rewriteEnvContent(content, map[string]string{"DB_HOST": "127.0.0.1"}, nil)
+
+	want := "DB_HOST=127.0.0.1\nDB_PORT=3306\n# DB_HOST=10.0.0.6\nDB_HOST=127.0.0.1\n"
+	if result.Content != want {
+		t.Errorf("content = %q, want %q", result.Content, want)
+	}
+	if len(result.Set) != 1 || result.Set[0].Key != "DB_HOST" || result.Set[0].Added {
+		t.Errorf("set = %+v, want one DB_HOST change", result.Set)
+	}
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const envGuardHookMarker = "# Installed by mo env:guard"
+
+const envGuardHook = `#!/bin/sh
+` + envGuardHookMarker + `
+if command -v mo >/dev/null 2>&1; then
+	exec mo env:guard
+fi
+echo "mo not found, skipping env:guard" >&2
+`
+
+func EnvGuard(cliContext *cli.Context) error {
+	if cliContext.Bool("install-hook") {
+		return installEnvGuardHook(cliContext.Bool("force"))
+	}
+
+	problems := 0
+	inGit := isGitRepository()
+
+	if inGit {
+		tracked, err := trackedEnvFiles()
+		if err != nil {
+			return err
+		}
+		for _, file := range tracked {
+			fmt.Printf("✗ %s is tracked or staged in git (git rm --cached %s)\n", file, file)
+			problems++
+		}
+	} else {
+		fmt.Println("Not a git repository, skipping the tracked files check.")
+	}
+
+	content, err := readEnvExample(inGit)
+	if err != nil {
+		return err
+	}
+	for _, issue := range envExampleSecrets(content) {
+		fmt.Printf("✗ .env.example:%d: %s\n", issue.Line, issue.Message)
+		problems++
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found, secrets may end up in the repository", problems)
+	}
+
+	fmt.Println("✓ No env secrets found in git")
+	return nil
+}
+
+// trackedEnvFiles returns env files in the git index, apart from the example
+// and the encrypted files env:encrypt writes to be committed
+func trackedEnvFiles() ([]string, error) {
+	output, err := exec.Command("git", "ls-files", "--cached", "--", ":(glob)**/.env", ":(glob)**/.env.*").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing git files: %v", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name := filepath.Base(file)
+		if file == "" || name == ".env.example" || strings.HasSuffix(name, ".encrypted") {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readEnvExample prefers the staged .env.example, which is what gets committed
+func readEnvExample(inGit bool) (string, error) {
+	if inGit {
+		if output, err := exec.Command("git", "show", ":.env.example").Output(); err == nil {
+			return string(output), nil
+		}
+	}
+
+	content, err := os.ReadFile(".env.example")
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading .env.example: %v", err)
+	}
+	return string(content), nil
+}
+
+// envExampleSecrets reports values in an example file that look like real secrets
+func envExampleSecrets(content string) []envLintIssue {
+	var issues []envLintIssue
+	for i, line := range strings.Split(content, "\n") {
+		_, key, value, ok := splitEnvAssignment(line)
+		if !ok {
+			continue
+		}
+
+		if looksLikeSecret(key, value, secretKeyPatterns) {
+			issues = append(issues, envLintIssue{i + 1, fmt.Sprintf("%s looks like a real secret (mo env:example regenerates the file)", key)})
+		}
+	}
+	return issues
+}
+
+func installEnvGuardHook(force bool) error {
+	if !isGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return fmt.Errorf("error finding the git hooks directory: %v", err)
+	}
+	hookPath := filepath.Join(strings.TrimSpace(string(output)), "pre-commit")
+
+	if existing, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(existing), envGuardHookMarker) && !force {
+		return fmt.Errorf("%s already exists, use --force to replace it or add \"mo env:guard\" to it", hookPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %v", filepath.Dir(hookPath), err)
+	}
+	if err := os.WriteFile(hookPath, []byte(envGuardHook), 0755); err != nil {
+		return fmt.Errorf("error writing %s: %v", hookPath, err)
+	}
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return fmt.Errorf("error making %s executable: %v", hookPath, err)
+	}
+
+	fmt.Printf("✓ pre-commit hook installed at %s\n", hookPath)
+	return nil
+}
+
+func isGitRepository() bool {
+	return exec.Command("git", "rev-parse", "--is-inside-work-tree").Run() == nil
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+func EnvHistory(cliContext *cli.Context) error {
+	entries, err := utils.ReadEnvJournal()
+	if err != nil {
+		return fmt.Errorf("error reading history: %v", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No .env changes recorded for this project.")
+		return nil
+	}
+
+	limit := cliContext.Int("limit")
+	showValues := cliContext.Bool("show-values")
+
+	fmt.Println("Recorded .env changes (newest first):")
+	fmt.Println("----------------------------")
+	shown := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		if limit > 0 && shown >= limit {
+			break
+		}
+		entry := entries[i]
+		oldValue := displayHistoryValue(entry.Key, entry.OldValue, showValues)
+		newValue := displayHistoryValue(entry.Key, entry.NewValue, showValues)
+
+		change := fmt.Sprintf("%s → %s", oldValue, newValue)
+		if entry.Added {
+			change = fmt.Sprintf("added %s", newValue)
+		} else if strings.HasPrefix(strings.TrimSpace(entry.NewLine), "#") {
+			change = "commented out"
+		}
+
+		fmt.Printf("%3d  %s  %-14s %-12s %s: %s\n",
+			len(entries)-i,
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Command,
+			relativeToCwd(entry.File),
+			entry.Key,
+			change,
+		)
+		shown++
+	}
+	fmt.Println("----------------------------")
+	fmt.Println("Undo the latest change with \"mo env:undo\", or the last n with \"mo env:undo n\".")
+
+	return nil
+}
+
+func EnvUndo(cliContext *cli.Context) error {
+	n := 1
+	if cliContext.Args().Len() > 0 {
+		parsed, err := strconv.Atoi(cliContext.Args().First())
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("invalid number of changes: %s", cliContext.Args().First())
+		}
+		n = parsed
+	}
+
+	undone, err := utils.UndoEnvChanges(n)
+	for _, entry := range undone {
+		fmt.Printf("Reverted %s in %s (%s)\n", entry.Key, relativeToCwd(entry.File), entry.Command)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(undone) == 0 {
+		fmt.Println("Nothing to undo.")
+	} else {
+		fmt.Printf("✓ Undid %d change(s)\n", len(undone))
+	}
+	return nil
+}
+
+func displayHistoryValue(key, value string, showValues bool) string {
+	if value == "" {
+		return `""`
+	}
+	if !showValues && looksLikeSecret(key, value, secretKeyPatterns) {
+		return "****"
+	}
+	return value
+}
+
+func relativeToCwd(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+var cipherConfigPattern = regexp.MustCompile(`['"]cipher['"]\s*=>\s*(?:env\(\s*['"][^'"]*['"]\s*,\s*)?['"]([^'"]+)['"]`)
+
+func EnvKey(cliContext *cli.Context) error {
+	cipherName := appCipher()
+
+	if cliContext.Bool("show") {
+		key, err := utils.GenerateKey(cipherName)
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+	}
+
+	envManager := utils.NewEnvManager(utils.EnvFilePath(cliContext.String("env")))
+	if !fileExists(envManager.Path) {
+		return fmt.Errorf("%s file not found", envManager.Path)
+	}
+
+	if _, err := generateAppKey(envManager, cipherName, cliContext.Bool("force")); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ APP_KEY set in %s (%s)\n", envManager.Path, cipherName)
+	return nil
+}
+
+// generateAppKey writes a new APP_KEY, refusing to replace an existing key unless force is set
+func generateAppKey(envManager *utils.EnvManager, cipherName string, force bool) (string, error) {
+	existing, found, err := envManager.GetVar("APP_KEY")
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("error reading APP_KEY: %v", err)
+	}
+	if found && utils.UnquoteValue(existing) != "" && !force {
+		return "", fmt.Errorf("APP_KEY is already set in %s, use --force to overwrite it", envManager.Path)
+	}
+
+	key, err := utils.GenerateKey(cipherName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := envManager.SetVar("APP_KEY", key); err != nil {
+		return "", fmt.Errorf("error writing APP_KEY: %v", err)
+	}
+	return key, nil
+}
+
+// appCipher returns the cipher configured in config/app.php, or Laravel's default
+func appCipher() string {
+	data, err := os.ReadFile("config/app.php")
+	if err != nil {
+		return utils.DefaultCipher
+	}
+
+	match := cipherConfigPattern.FindSubmatch(data)
+	if match == nil {
+		return utils.DefaultCipher
+	}
+	return string(match[1])
+}
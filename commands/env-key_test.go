@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mo/utils"
+)
+
+func TestGenerateAppKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		cipher  string
+		force   bool
+		wantLen int
+		wantErr string
+	}{
+		{name: "empty key", env: "APP_KEY=\n", cipher: "AES-256-CBC", wantLen: 32},
+		{name: "missing key", env: "APP_NAME=Shop\n", cipher: "AES-128-CBC", wantLen: 16},
+		{name: "gcm", env: "APP_KEY=\n", cipher: "AES-256-GCM", wantLen: 32},
+		{name: "existing key", env: "APP_KEY=base64:c2VjcmV0\n", cipher: "AES-256-CBC", wantErr: "already set"},
+		{name: "existing key with force", env: "APP_KEY=base64:c2VjcmV0\n", cipher: "AES-256-CBC", force: true, wantLen: 32},
+		{name: "unsupported cipher", env: "APP_KEY=\n", cipher: "AES-192-CBC", wantErr: "unsupported cipher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte(tt.env), 0644); err != nil {
+				t.Fatal(err)
+			}
+			envManager := utils.NewEnvManager(path)
+
+			key, err := generateAppKey(envManager, tt.cipher, tt.force)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if content, _ := os.ReadFile(path); string(content) != tt.env {
+					t.Errorf("%s was changed to %q", path, content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rawKey, err := utils.ParseKey(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rawKey) != tt.wantLen {
+				t.Errorf("key length = %d, want %d", len(rawKey), tt.wantLen)
+			}
+			if written, _, _ := envManager.GetVar("APP_KEY"); written != key {
+				t.Errorf("APP_KEY = %q, want %q", written, key)
+			}
+		})
+	}
+}
+
+func TestGCMAppKeyCannotEncryptEnv(t *testing.T) {
+	key, err := utils.GenerateKey("AES-256-GCM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawKey, _ := utils.ParseKey(key)
+
+	// artisan env:encrypt only supports the CBC ciphers
+	if _, err := utils.EncryptEnv([]byte("APP_ENV=local\n"), rawKey, "AES-256-GCM"); err == nil {
+		t.Error("expected GCM to be rejected for env encryption")
+	}
+}
+
+func TestAppCipher(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "no config", want: utils.DefaultCipher},
+		{name: "literal", config: "<?php\nreturn [\n    'cipher' => 'AES-128-CBC',\n];\n", want: "AES-128-CBC"},
+		{name: "env default", config: "<?php\nreturn [\n    \"cipher\" => env(\"APP_CIPHER\", \"AES-256-GCM\"),\n];\n", want: "AES-256-GCM"},
+		{name: "no cipher", config: "<?php\nreturn [\n    'name' => 'Shop',\n];\n", want: utils.DefaultCipher},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldWd, _ := os.Getwd()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(oldWd)
+
+			if tt.config != "" {
+				if err := os.MkdirAll("config", 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile("config/app.php", []byte(tt.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := appCipher(); got != tt.want {
+				t.Errorf("appCipher() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var envAssignmentPattern = regexp.MustCompile(`^(\s*)(export\s+)?([A-Za-z_][A-Za-z0-9_.]*)(\s*)=(\s*)(.*)$`)
+
+// envAssignment is where a key is assigned, index is the position in the formatted lines
+type envAssignment struct {
+	index int
+	line  int
+}
+
+// envLintIssue is a problem found in an env file, Line is 1-based
+type envLintIssue struct {
+	Line    int
+	Message string
+}
+
+func EnvLint(cliContext *cli.Context) error {
+	problems := 0
+	for _, file := range envLintFiles(cliContext) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", file, err)
+		}
+
+		_, issues := formatEnvContent(string(content))
+		for _, issue := range issues {
+			fmt.Printf("%s:%d: %s\n", file, issue.Line, issue.Message)
+		}
+		problems += len(issues)
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found, run \"mo env:fmt\" to fix them", problems)
+	}
+
+	fmt.Println("✓ No problems found")
+	return nil
+}
+
+func EnvFmt(cliContext *cli.Context) error {
+	for _, file := range envLintFiles(cliContext) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", file, err)
+		}
+
+		formatted, issues := formatEnvContent(string(content))
+		if len(issues) == 0 {
+			fmt.Printf("✓ %s is already formatted\n", file)
+			continue
+		}
+
+		if err := os.WriteFile(file, []byte(formatted), 0644); err != nil {
+			return fmt.Errorf("error writing %s: %v", file, err)
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s:%d: fixed %s\n", file, issue.Line, issue.Message)
+		}
+		fmt.Printf("✓ %s formatted (%d fix(es))\n", file, len(issues))
+	}
+
+	return nil
+}
+
+func envLintFiles(cliContext *cli.Context) []string {
+	if cliContext.Args().Len() > 0 {
+		return cliContext.Args().Slice()
+	}
+	return []string{".env"}
+}
+
+// formatEnvContent normalizes every assignment to KEY=value and returns the
+// fixed content with the issues it found. Comments, blank lines and order are
+// kept. phpdotenv uses the last assignment of a key, so earlier duplicates are
+// removed. Commenting them out would leave lines SetVar uncomments and edits
+// while the last one still wins.
+func formatEnvContent(content string) (string, []envLintIssue) {
+	lines := strings.Split(content, "\n")
+	var issues []envLintIssue
+	var formatted []string
+	assignments := map[string][]envAssignment{}
+	var keys []string
+
+	for i, line := range lines {
+		lineNumber := i + 1
+		// The empty element after the final newline is not a line
+		if i == len(lines)-1 && line == "" {
+			formatted = append(formatted, line)
+			break
+		}
+
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed != line {
+			issues = append(issues, envLintIssue{lineNumber, "trailing whitespace"})
+			line = trimmed
+		}
+
+		match := envAssignmentPattern.FindStringSubmatch(line)
+		if match == nil {
+			formatted = append(formatted, line)
+			continue
+		}
+		indent, export, key, beforeEquals, afterEquals, value := match[1], match[2], match[3], match[4], match[5], match[6]
+
+		if indent != "" {
+			issues = append(issues, envLintIssue{lineNumber, fmt.Sprintf("indentation before %s", key)})
+		}
+		if beforeEquals != "" || afterEquals != "" {
+			issues = append(issues, envLintIssue{lineNumber, fmt.Sprintf("spaces around '=' in %s", key)})
+		}
+		if upper := strings.ToUpper(key); upper != key {
+			issues = append(issues, envLintIssue{lineNumber, fmt.Sprintf("lowercase key %s (should be %s)", key, upper)})
+			key = upper
+		}
+		if quoted, changed := quoteEnvValue(value); changed {
+			issues = append(issues, envLintIssue{lineNumber, fmt.Sprintf("unquoted value with spaces in %s", key)})
+			value = quoted
+		}
+
+		if export != "" {
+			export = "export "
+		}
+		line = export + key + "=" + value
+
+		if _, ok := assignments[key]; !ok {
+			keys = append(keys, key)
+		}
+		assignments[key] = append(assignments[key], envAssignment{index: len(formatted), line: lineNumber})
+		formatted = append(formatted, line)
+	}
+
+	dropped := map[int]bool{}
+	for _, key := range keys {
+		occurrences := assignments[key]
+		last := occurrences[len(occurrences)-1]
+		for _, earlier := range occurrences[:len(occurrences)-1] {
+			issues = append(issues, envLintIssue{earlier.line, fmt.Sprintf("duplicate key %s (overridden on line %d)", key, last.line)})
+			dropped[earlier.index] = true
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	kept := formatted[:0]
+	for i, line := range formatted {
+		if !dropped[i] {
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.Join(kept, "\n"), issues
+}
+
+// quoteEnvValue wraps an unquoted value containing whitespace in quotes. An
+// inline comment (" # ...") is kept outside the quotes.
+func quoteEnvValue(value string) (string, bool) {
+	if value == "" || value[0] == '"' || value[0] == '\'' {
+		return value, false
+	}
+
+	comment := ""
+	if index := strings.Index(value, " #"); index >= 0 {
+		value, comment = strings.TrimRight(value[:index], " \t"), value[index:]
+	}
+	if !strings.ContainsAny(value, " \t") {
+		return value + comment, false
+	}
+
+	switch {
+	case !strings.Contains(value, `"`):
+		value = `"` + value + `"`
+	case !strings.Contains(value, "'"):
+		value = "'" + value + "'"
+	default:
+		value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+	return value + comment, true
+}
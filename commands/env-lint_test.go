@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mo/utils"
+)
+
+func TestFormatEnvContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		wantIssues []string
+	}{
+		{
+			name:    "clean file",
+			content: "APP_NAME=\"My Shop\"\n\n# Database\nDB_HOST=127.0.0.1\n",
+			want:    "APP_NAME=\"My Shop\"\n\n# Database\nDB_HOST=127.0.0.1\n",
+		},
+		{
+			name:       "trailing whitespace",
+			content:    "APP_ENV=local  \n",
+			want:       "APP_ENV=local\n",
+			wantIssues: []string{"1: trailing whitespace"},
+		},
+		{
+			name:       "spaces around equals",
+			content:    "APP_ENV = local\n",
+			want:       "APP_ENV=local\n",
+			wantIssues: []string{"1: spaces around '=' in APP_ENV"},
+		},
+		{
+			name:       "lowercase key",
+			content:    "app_env=local\n",
+			want:       "APP_ENV=local\n",
+			wantIssues: []string{"1: lowercase key app_env (should be APP_ENV)"},
+		},
+		{
+			name:       "unquoted value with spaces",
+			content:    "APP_NAME=My Shop # shown in mails\nMAIL_FROM_NAME=Say \"hi\"\n",
+			want:       "APP_NAME=\"My Shop\" # shown in mails\nMAIL_FROM_NAME='Say \"hi\"'\n",
+			wantIssues: []string{"1: unquoted value with spaces in APP_NAME", "2: unquoted value with spaces in MAIL_FROM_NAME"},
+		},
+		{
+			name:       "duplicates",
+			content:    "DB_HOST=127.0.0.1\nDB_PORT=3306\n\nDB_HOST=127.0.0.1\ndb_port=3307\n",
+			want:       "\nDB_HOST=127.0.0.1\nDB_PORT=3307\n",
+			wantIssues: []string{"1: duplicate key DB_HOST (overridden on line 4)", "2: duplicate key DB_PORT (overridden on line 5)", "5: lowercase key db_port (should be DB_PORT)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues := formatEnvContent(tt.content)
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+
+			var gotIssues []string
+			for _, issue := range issues {
+				gotIssues = append(gotIssues, fmt.Sprintf("%d: %s", issue.Line, issue.Message))
+			}
+			if strings.Join(gotIssues, "|") != strings.Join(tt.wantIssues, "|") {
+				t.Errorf("issues = %v, want %v", gotIssues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestFormatEnvContentThenSetVar(t *testing.T) {
+	formatted, _ := formatEnvContent("DB_PORT=3306\nDB_HOST=127.0.0.1\nDB_PORT=3307\n")
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// SetVar edits the first matching line, which must be the one that wins
+	envManager := utils.NewEnvManager(path)
+	if err := envManager.SetVar("DB_PORT", "3308"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "DB_HOST=127.0.0.1\nDB_PORT=3308\n"; string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
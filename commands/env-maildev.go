@@ -8,32 +8,28 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// mailDevSettings are the .env values for a local MailDev (or mail:catch) server
+var mailDevSettings = [][2]string{
+	{"MAIL_MAILER", "smtp"},
+	{"MAIL_HOST", "127.0.0.1"},
+	{"MAIL_PORT", "2525"},
+	{"MAIL_USERNAME", ""},
+	{"MAIL_PASSWORD", ""},
+	{"MAIL_ENCRYPTION", ""},
+	{"MAIL_FROM_ADDRESS", ""},
+}
+
 func EnvMailDev(cliContext *cli.Context) error {
 	envManager := utils.NewEnvManager(".env")
 
-	if err := envManager.SetVar("MAIL_MAILER", "smtp"); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_HOST", "127.0.0.1"); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_PORT", "2525"); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_USERNAME", ""); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_PASSWORD", ""); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_ENCRYPTION", ""); err != nil {
-		log.Fatal(err)
-	}
-	if err := envManager.SetVar("MAIL_FROM_ADDRESS", ""); err != nil {
-		log.Fatal(err)
+	for _, setting := range mailDevSettings {
+		if err := envManager.SetVar(setting[0], setting[1]); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	fmt.Println(".env file updated with MailDev settings.")
+	fmt.Println("Run \"mo mail:catch\" to receive mails without installing MailDev.")
 
 	return nil
 }
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// envCallPattern matches the start of env('KEY') and env('KEY', default) calls in PHP code
+var envCallPattern = regexp.MustCompile(`\benv\(\s*['"]([A-Za-z0-9_]+)['"]\s*([,)])`)
+
+var numericPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+var envScanDirs = []string{"config", "app", "routes"}
+
+// envUsage describes where a key is read and which default the code falls back to
+type envUsage struct {
+	Key        string
+	Default    string
+	HasDefault bool
+	Location   string
+}
+
+func EnvScan(cliContext *cli.Context) error {
+	usages, err := scanEnvUsages(envScanDirs)
+	if err != nil {
+		return err
+	}
+
+	if !fileExists(".env.example") {
+		return fmt.Errorf(".env.example not found")
+	}
+	exampleKeys, err := getEnvVariablesFrom(".env.example")
+	if err != nil {
+		return fmt.Errorf("error reading .env.example: %v", err)
+	}
+
+	var missing []envUsage
+	for _, key := range sortedUsageKeys(usages) {
+		if !contains(exampleKeys, key) {
+			missing = append(missing, usages[key])
+		}
+	}
+
+	var unused []string
+	for _, key := range exampleKeys {
+		if key == "" || strings.HasPrefix(key, "VITE_") {
+			continue
+		}
+		if _, ok := usages[key]; !ok && !contains(unused, key) {
+			unused = append(unused, key)
+		}
+	}
+
+	fmt.Printf("Found %d env() key(s) in %s\n", len(usages), strings.Join(envScanDirs, ", "))
+	fmt.Println("----------------------------")
+
+	if len(missing) == 0 {
+		fmt.Println("No keys missing from .env.example")
+	} else {
+		fmt.Println("Missing from .env.example:")
+		for _, usage := range missing {
+			fmt.Printf("  %-30s default: %-20s %s\n", usage.Key, describeDefault(usage), usage.Location)
+		}
+	}
+
+	fmt.Println()
+	if len(unused) == 0 {
+		fmt.Println("No unused keys in .env.example")
+	} else {
+		fmt.Println("In .env.example but not read by config/, app/ or routes/:")
+		for _, key := range unused {
+			fmt.Printf("  %s\n", key)
+		}
+	}
+
+	if cliContext.Bool("defaults") {
+		fmt.Println()
+		fmt.Println("Defaults found in code:")
+		for _, key := range sortedUsageKeys(usages) {
+			if usages[key].HasDefault {
+				fmt.Printf("  %-30s %s\n", key, usages[key].Default)
+			}
+		}
+	}
+	fmt.Println("----------------------------")
+
+	if cliContext.Bool("add") && len(missing) > 0 {
+		exampleManager := utils.NewEnvManager(".env.example")
+		for _, usage := range missing {
+			if err := exampleManager.SetVar(usage.Key, envValueFromDefault(usage)); err != nil {
+				return fmt.Errorf("error adding %s to .env.example: %v", usage.Key, err)
+			}
+		}
+		fmt.Printf("Added %d missing key(s) to .env.example\n", len(missing))
+	} else if len(missing) > 0 {
+		fmt.Println("Run with --add to add the missing keys with their defaults.")
+	}
+
+	return nil
+}
+
+// scanEnvUsages walks the given directories for env() calls, keeping the first usage of each key
+func scanEnvUsages(dirs []string) (map[string]envUsage, error) {
+	usages := map[string]envUsage{}
+
+	for _, dir := range dirs {
+		if !fileExists(dir) {
+			continue
+		}
+
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".php") {
+				return nil
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			for _, match := range envCallPattern.FindAllSubmatchIndex(data, -1) {
+				key := string(data[match[2]:match[3]])
+				hasDefault := data[match[4]] == ','
+
+				// Prefer the first usage that actually declares a default
+				if existing, seen := usages[key]; seen && (existing.HasDefault || !hasDefault) {
+					continue
+				}
+
+				usage := envUsage{
+					Key:      key,
+					Location: fmt.Sprintf("%s:%d", path, strings.Count(string(data[:match[0]]), "\n")+1),
+				}
+				if hasDefault {
+					usage.Default = phpArgument(data[match[5]:])
+					usage.HasDefault = true
+				}
+				usages[key] = usage
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s: %v", dir, err)
+		}
+	}
+
+	return usages, nil
+}
+
+// phpArgument returns the PHP expression at the start of data, up to the next
+// top-level comma or closing parenthesis
+func phpArgument(data []byte) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case (c == ')' || c == ']') && depth > 0:
+			depth--
+		case (c == ',' || c == ')') && depth == 0:
+			return strings.TrimSpace(string(data[:i]))
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func sortedUsageKeys(usages map[string]envUsage) []string {
+	keys := make([]string, 0, len(usages))
+	for key := range usages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func describeDefault(usage envUsage) string {
+	if !usage.HasDefault {
+		return "(none)"
+	}
+	return usage.Default
+}
+
+// envValueFromDefault converts a PHP default expression into a .env value.
+// Only literals are converted, anything else (nested calls, constants) becomes empty.
+func envValueFromDefault(usage envUsage) string {
+	if !usage.HasDefault {
+		return ""
+	}
+
+	value := usage.Default
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		unquoted := strings.ReplaceAll(value[1:len(value)-1], `\`+string(value[0]), string(value[0]))
+		if strings.ContainsAny(unquoted, " #\"") {
+			return fmt.Sprintf("%q", unquoted)
+		}
+		return unquoted
+	}
+
+	switch strings.ToLower(value) {
+	case "null":
+		return ""
+	case "true", "false":
+		return strings.ToLower(value)
+	}
+
+	if numericPattern.MatchString(value) {
+		return value
+	}
+	return ""
+}
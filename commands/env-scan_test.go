@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanEnvUsages(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `<?php
+return [
+    'name' => env('APP_NAME', 'Laravel'),
+    'debug' => (bool) env('APP_DEBUG', false),
+    'redis' => env('REDIS_URL'),
+    'fallback' => env('CACHE_PREFIX', env('APP_NAME', 'x') . '_cache'),
+    'other' => getenv('NOT_MATCHED'),
+];
+`
+	if err := os.WriteFile(filepath.Join(configDir, "app.php"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usages, err := scanEnvUsages([]string{configDir})
+	if err != nil {
+		t.Fatalf("scanEnvUsages() error = %v", err)
+	}
+
+	tests := []struct {
+		key        string
+		hasDefault bool
+		defaultVal string
+	}{
+		{"APP_NAME", true, "'Laravel'"},
+		{"APP_DEBUG", true, "false"},
+		{"REDIS_URL", false, ""},
+		{"CACHE_PREFIX", true, "env('APP_NAME', 'x') . '_cache'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			usage, ok := usages[tt.key]
+			if !ok {
+				t.Fatalf("%s not found", tt.key)
+			}
+			if usage.HasDefault != tt.hasDefault || usage.Default != tt.defaultVal {
+				t.Errorf("usage = %+v, want default %q (%v)", usage, tt.defaultVal, tt.hasDefault)
+			}
+		})
+	}
+
+	if _, ok := usages["NOT_MATCHED"]; ok {
+		t.Error("getenv() call should not be reported")
+	}
+}
+
+func TestEnvValueFromDefault(t *testing.T) {
+	tests := []struct {
+		defaultVal string
+		want       string
+	}{
+		{"'Laravel'", "Laravel"},
+		{"'My App'", `"My App"`},
+		{"false", "false"},
+		{"6379", "6379"},
+		{"null", ""},
+		{"env('OTHER')", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.defaultVal, func(t *testing.T) {
+			usage := envUsage{Default: tt.defaultVal, HasDefault: true}
+			if got := envValueFromDefault(usage); got != tt.want {
+				t.Errorf("envValueFromDefault(%q) = %q, want %q", tt.defaultVal, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,76 +4,163 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path"
 	"strings"
 
+	"mo/config"
 	"mo/utils"
 
 	"github.com/urfave/cli/v2"
 )
 
 func SyncEnv(cliContext *cli.Context) error {
-	// envManager := utils.NewEnvManager(".env")
-	exampleManager := utils.NewEnvManager(".env.example")
+	source, targets, err := envSyncTargets(cliContext)
+	if err != nil {
+		return err
+	}
 
-	if _, err := os.Stat(".env.example"); os.IsNotExist(err) {
-		if err := os.WriteFile(".env.example", []byte{}, 0644); err != nil {
-			return fmt.Errorf("error creating .env.example: %v", err)
+	for i, target := range targets {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := syncEnvTarget(source, target); err != nil {
+			return err
 		}
-		fmt.Println(".env.example file created.")
 	}
 
-	envVariables, err := getEnvVariablesFrom(".env")
+	return nil
+}
+
+// envSyncTargets resolves source and targets from the arguments, then .mo.json,
+// then the default of syncing .env into .env.example
+func envSyncTargets(cliContext *cli.Context) (string, []config.EnvSyncTarget, error) {
+	source := ".env"
+	var targets []config.EnvSyncTarget
+
+	projectConfig, err := config.LoadProjectConfig(".")
 	if err != nil {
-		return fmt.Errorf("error reading .env: %v", err)
+		return "", nil, err
+	}
+	if projectConfig.EnvSync != nil {
+		if projectConfig.EnvSync.Source != "" {
+			source = projectConfig.EnvSync.Source
+		}
+		targets = projectConfig.EnvSync.Targets
 	}
 
-	exampleVariables, err := getEnvVariablesFrom(".env.example")
-	if err != nil {
-		return fmt.Errorf("error reading .env.example: %v", err)
+	if cliContext.IsSet("source") {
+		source = cliContext.String("source")
 	}
 
-	var difference []string
-	for _, variable := range envVariables {
-		if !contains(exampleVariables, variable) {
-			difference = append(difference, variable)
+	if cliContext.Args().Len() > 0 {
+		targets = nil
+		for _, file := range cliContext.Args().Slice() {
+			targets = append(targets, config.EnvSyncTarget{File: file})
 		}
 	}
 
-	for i := 0; i < len(difference); i++ {
-		if strings.TrimSpace(difference[i]) == "" {
-			difference = append(difference[:i], difference[i+1:]...)
-			i--
+	if len(targets) == 0 {
+		targets = []config.EnvSyncTarget{{File: ".env.example"}}
+	}
+	targets = applyEnvSyncFlags(targets, cliContext.StringSlice("ignore"), cliContext.Bool("with-values"))
+
+	for _, target := range targets {
+		if target.File == source {
+			return "", nil, fmt.Errorf("target %s is the same as the source", target.File)
 		}
 	}
 
-	differenceMap := make(map[string]bool)
-	for _, variable := range difference {
-		differenceMap[variable] = true
+	return source, targets, nil
+}
+
+// applyEnvSyncFlags adds --ignore patterns and --with-values to every target,
+// including the ones configured in .mo.json
+func applyEnvSyncFlags(targets []config.EnvSyncTarget, ignore []string, withValues bool) []config.EnvSyncTarget {
+	applied := make([]config.EnvSyncTarget, len(targets))
+	for i, target := range targets {
+		target.Ignore = append(append([]string{}, target.Ignore...), ignore...)
+		target.CopyValues = target.CopyValues || withValues
+		applied[i] = target
 	}
-	difference = []string{}
-	for variable := range differenceMap {
+	return applied
+}
+
+func syncEnvTarget(source string, target config.EnvSyncTarget) error {
+	sourceManager := utils.NewEnvManager(source)
+	targetManager := utils.NewEnvManager(target.File)
+
+	if _, err := os.Stat(target.File); os.IsNotExist(err) {
+		if err := os.WriteFile(target.File, []byte{}, 0644); err != nil {
+			return fmt.Errorf("error creating %s: %v", target.File, err)
+		}
+		fmt.Printf("%s file created.\n", target.File)
+	}
+
+	sourceVariables, err := getEnvVariablesFrom(source)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", source, err)
+	}
+
+	targetVariables, err := getEnvVariablesFrom(target.File)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", target.File, err)
+	}
+
+	var difference []string
+	var ignored []string
+	for _, variable := range sourceVariables {
+		if strings.TrimSpace(variable) == "" || contains(targetVariables, variable) || contains(difference, variable) {
+			continue
+		}
+		if isIgnoredEnvKey(variable, target.Ignore) {
+			if !contains(ignored, variable) {
+				ignored = append(ignored, variable)
+			}
+			continue
+		}
 		difference = append(difference, variable)
 	}
 
 	for _, variable := range difference {
-		fmt.Printf("Adding %s to .env.example\n", variable)
-		if err := exampleManager.SetVar(variable, ""); err != nil {
-			return fmt.Errorf("error adding variable %s to .env.example: %v", variable, err)
+		value := ""
+		if target.CopyValues {
+			value, _, err = sourceManager.GetVar(variable)
+			if err != nil {
+				return fmt.Errorf("error reading %s from %s: %v", variable, source, err)
+			}
+		}
+
+		fmt.Printf("Adding %s to %s\n", variable, target.File)
+		if err := targetManager.SetVar(variable, value); err != nil {
+			return fmt.Errorf("error adding variable %s to %s: %v", variable, target.File, err)
 		}
 	}
 
 	if len(difference) == 0 {
-		fmt.Println("No missing variables found")
+		fmt.Printf("No missing variables found in %s\n", target.File)
 	} else {
-		fmt.Println("Added missing variables to .env.example:")
+		fmt.Printf("Added missing variables to %s:\n", target.File)
 		for _, variable := range difference {
 			fmt.Println(variable)
 		}
 	}
 
+	if len(ignored) > 0 {
+		fmt.Printf("Ignored for %s: %s\n", target.File, strings.Join(ignored, ", "))
+	}
+
 	return nil
 }
 
+func isIgnoredEnvKey(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func contains(slice []string, s string) bool {
 	for _, item := range slice {
 		if item == s {
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mo/config"
+)
+
+func TestApplyEnvSyncFlags(t *testing.T) {
+	configured := []config.EnvSyncTarget{
+		{File: ".env.testing", Ignore: []string{"MAIL_*"}},
+		{File: ".env.dusk.local", CopyValues: true},
+	}
+
+	targets := applyEnvSyncFlags(configured, []string{"DUSK_*"}, false)
+	if got := strings.Join(targets[0].Ignore, ","); got != "MAIL_*,DUSK_*" {
+		t.Errorf("ignore = %s, want MAIL_*,DUSK_*", got)
+	}
+	if got := strings.Join(targets[1].Ignore, ","); got != "DUSK_*" {
+		t.Errorf("ignore = %s, want DUSK_*", got)
+	}
+	if targets[0].CopyValues || !targets[1].CopyValues {
+		t.Errorf("copy values = %v, %v, want false, true", targets[0].CopyValues, targets[1].CopyValues)
+	}
+	if len(configured[0].Ignore) != 1 {
+		t.Errorf("configured targets were modified: %+v", configured)
+	}
+
+	targets = applyEnvSyncFlags(configured, nil, true)
+	if !targets[0].CopyValues || !targets[1].CopyValues {
+		t.Error("--with-values should copy values into every target")
+	}
+}
+
+func TestSyncEnvTarget(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, ".env")
+	if err := os.WriteFile(source, []byte("APP_NAME=Shop\nDB_HOST=127.0.0.1\nDUSK_DRIVER_URL=http://selenium:4444\nMAIL_MAILER=log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		target config.EnvSyncTarget
+		want   string
+	}{
+		{
+			name:   "ignore globs",
+			target: config.EnvSyncTarget{File: "ignore", Ignore: []string{"DUSK_*", "MAIL_MAILER"}},
+			want:   "APP_NAME=Shop\nDB_HOST=",
+		},
+		{
+			name:   "copy values",
+			target: config.EnvSyncTarget{File: "values", Ignore: []string{"DUSK_*"}, CopyValues: true},
+			want:   "APP_NAME=Shop\nDB_HOST=127.0.0.1\nMAIL_MAILER=log",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.target.File = filepath.Join(dir, tt.target.File)
+			if err := os.WriteFile(tt.target.File, []byte("APP_NAME=Shop"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := syncEnvTarget(source, tt.target); err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := os.ReadFile(tt.target.File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("content = %q, want %q", content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"mo/config"
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// testingEnvSettings keep test runs fast and free of side effects
+var testingEnvSettings = map[string]string{
+	"APP_ENV":          "testing",
+	"BCRYPT_ROUNDS":    "4",
+	"CACHE_STORE":      "array",
+	"QUEUE_CONNECTION": "sync",
+	"MAIL_MAILER":      "array",
+	"SESSION_DRIVER":   "array",
+}
+
+func EnvTesting(cliContext *cli.Context) error {
+	source := cliContext.String("source")
+	output := cliContext.String("output")
+
+	if fileExists(output) && !cliContext.Bool("force") {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", output)
+	}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", source, err)
+	}
+
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return err
+	}
+	testingConfig := projectConfig.EnvTesting
+	if testingConfig == nil {
+		testingConfig = &config.EnvTestingConfig{}
+	}
+
+	database := cliContext.String("database")
+	if database == "" {
+		database = testingConfig.Database
+	}
+	if database == "" {
+		database = "sqlite"
+	}
+
+	set := map[string]string{}
+	for key, value := range testingEnvSettings {
+		set[key] = value
+	}
+	if key := cacheStoreKey(projectLaravelVersion(), utils.NewEnvManager(source)); key != "CACHE_STORE" {
+		delete(set, "CACHE_STORE")
+		set[key] = "array"
+	}
+
+	switch database {
+	case "sqlite":
+		set["DB_CONNECTION"] = "sqlite"
+		set["DB_DATABASE"] = ":memory:"
+	case "mysql":
+		dbName, err := testingDatabaseName(source)
+		if err != nil {
+			return err
+		}
+		set["DB_DATABASE"] = dbName
+
+		if err := createDatabase(dbName, true); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Database '%s' is ready\n", dbName)
+	default:
+		return fmt.Errorf("unsupported database %q (supported: sqlite, mysql)", database)
+	}
+
+	for key, value := range testingConfig.Set {
+		set[key] = value
+	}
+
+	result := rewriteEnvContent(string(content), set, testingConfig.Drop)
+
+	if err := os.WriteFile(output, []byte(result.Content), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", output, err)
+	}
+
+	fmt.Println("----------------------------")
+	printEnvRewriteSummary(result)
+	fmt.Println("----------------------------")
+	fmt.Printf("✓ %s generated from %s\n", output, source)
+	return nil
+}
+
+// testingDatabaseName returns "<db>_test" for the MySQL database of the env file
+func testingDatabaseName(envPath string) (string, error) {
+	envManager := utils.NewEnvManager(envPath)
+
+	connection, _, err := envManager.GetVar("DB_CONNECTION")
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", envPath, err)
+	}
+	if connection = utils.UnquoteValue(connection); connection != "mysql" && connection != "mariadb" {
+		return "", fmt.Errorf("a separate test database needs DB_CONNECTION=mysql in %s (found %q)", envPath, connection)
+	}
+
+	dbName, _, err := envManager.GetVar("DB_DATABASE")
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", envPath, err)
+	}
+	dbName = utils.UnquoteValue(dbName)
+	if dbName == "" {
+		dbName = projectDatabaseName()
+	}
+	return dbName + "_test", nil
+}
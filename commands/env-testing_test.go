@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTestingDatabaseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "mysql", content: "DB_CONNECTION=mysql\nDB_DATABASE=shop\n", want: "shop_test"},
+		{name: "quoted", content: "DB_CONNECTION=\"mariadb\"\nDB_DATABASE=\"shop\"\n", want: "shop_test"},
+		{name: "sqlite", content: "DB_CONNECTION=sqlite\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envPath := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(envPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := testingDatabaseName(envPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
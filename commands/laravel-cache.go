@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// defaultCacheCommands are the artisan commands l:cache runs when neither the
+// config nor the Laravel version says otherwise
+var defaultCacheCommands = []string{"config:cache", "route:cache", "view:cache", "event:cache"}
+
+func LaravelCache(cliContext *cli.Context) error {
+	if !fileExists("artisan") {
+		return fmt.Errorf("not a Laravel project")
+	}
+
+	commands, err := artisanCommandList("cache")
+	if err != nil {
+		return err
+	}
+
+	if err := runArtisanCommands(commands, cliContext.Bool("continue-on-error")); err != nil {
+		return err
+	}
+
+	fmt.Println("✓ All Laravel caches built!")
+	return nil
+}
@@ -2,29 +2,95 @@ package commands
 
 import (
 	"fmt"
+	"strings"
+
+	"mo/config"
 	"mo/utils"
 
 	"github.com/urfave/cli/v2"
 )
 
+// defaultClearCommands are the artisan commands l:clear runs when neither the
+// config nor the Laravel version says otherwise
+var defaultClearCommands = []string{"cache:clear", "route:clear", "config:clear", "view:clear"}
+
 func LaravelClear(cliContext *cli.Context) error {
 	if !fileExists("artisan") {
 		return fmt.Errorf("not a Laravel project")
 	}
 
-	commands := [][]string{
-		{"php", "artisan", "cache:clear"},
-		{"php", "artisan", "route:clear"},
-		{"php", "artisan", "config:clear"},
-		{"php", "artisan", "view:clear"},
+	commands, err := artisanCommandList("clear")
+	if err != nil {
+		return err
 	}
 
-	for _, cmd := range commands {
-		if err := utils.RunCommand(cmd[0], cmd[1:]...); err != nil {
-			return fmt.Errorf("error running %s: %w", cmd[2], err)
-		}
+	if err := runArtisanCommands(commands, cliContext.Bool("continue-on-error")); err != nil {
+		return err
 	}
 
 	fmt.Println("✓ All Laravel caches cleared!")
 	return nil
 }
+
+// artisanCommandList returns the artisan commands for l:clear or l:cache. The
+// project's .mo.json takes precedence over the global config and the defaults
+// for the installed Laravel version.
+func artisanCommandList(kind string) ([]string, error) {
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	defaults := defaultArtisanCommands(kind, projectLaravelVersion())
+	candidates := [][]string{projectConfig.LaravelClear, cfg.LaravelClear, defaults}
+	if kind == "cache" {
+		candidates = [][]string{projectConfig.LaravelCache, cfg.LaravelCache, defaults}
+	}
+
+	for _, commands := range candidates {
+		if len(commands) > 0 {
+			return commands, nil
+		}
+	}
+	return nil, nil
+}
+
+// runArtisanCommands runs each command ("queue:restart", "view:clear --quiet", ...)
+// with php artisan. With continueOnError all commands run and a summary is printed.
+func runArtisanCommands(commands []string, continueOnError bool) error {
+	var failed []string
+
+	for _, command := range commands {
+		args := append([]string{"artisan"}, strings.Fields(command)...)
+		if err := utils.RunProjectCommand("php", args...); err != nil {
+			if !continueOnError {
+				return fmt.Errorf("error running %s: %w", command, err)
+			}
+			failed = append(failed, command)
+		}
+	}
+
+	if !continueOnError {
+		return nil
+	}
+
+	fmt.Println("----------------------------")
+	for _, command := range commands {
+		if contains(failed, command) {
+			fmt.Printf("✗ %s\n", command)
+		} else {
+			fmt.Printf("✓ %s\n", command)
+		}
+	}
+	fmt.Println("----------------------------")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d commands failed: %s", len(failed), len(commands), strings.Join(failed, ", "))
+	}
+	return nil
+}
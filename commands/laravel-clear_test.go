@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtisanCommandList(t *testing.T) {
+	tests := []struct {
+		name          string
+		globalConfig  string
+		projectConfig string
+		kind          string
+		want          []string
+	}{
+		{
+			name: "defaults",
+			kind: "clear",
+			want: defaultClearCommands,
+		},
+		{
+			name: "cache defaults",
+			kind: "cache",
+			want: defaultCacheCommands,
+		},
+		{
+			name:         "global config",
+			globalConfig: `{"laravel_clear": ["optimize:clear", "queue:restart"]}`,
+			kind:         "clear",
+			want:         []string{"optimize:clear", "queue:restart"},
+		},
+		{
+			name:          "project config wins",
+			globalConfig:  `{"laravel_clear": ["optimize:clear"]}`,
+			projectConfig: `{"laravel_clear": ["optimize:clear", "filament:clear-cached-components"]}`,
+			kind:          "clear",
+			want:          []string{"optimize:clear", "filament:clear-cached-components"},
+		},
+		{
+			name:          "clear list does not affect cache",
+			projectConfig: `{"laravel_clear": ["optimize:clear"]}`,
+			kind:          "cache",
+			want:          defaultCacheCommands,
+		},
+	}
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if tt.globalConfig != "" {
+				configPath := filepath.Join(home, ".config", "mortimer", "config.json")
+				if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(configPath, []byte(tt.globalConfig), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dir := t.TempDir()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			if tt.projectConfig != "" {
+				if err := os.WriteFile(".mo.json", []byte(tt.projectConfig), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := artisanCommandList(tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+
+	"mo/utils"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// projectDatabase is the database of the project's .env. MySQL and MariaDB are
+// queried with the driver, sqlite through the sqlite3 CLI since there is no
+// sqlite driver without cgo.
+type projectDatabase struct {
+	db         *sql.DB
+	sqlitePath string
+}
+
+// databaseSettings reads DB_CONNECTION and the connection keys from the env file
+func databaseSettings(envManager *utils.EnvManager) map[string]string {
+	values := map[string]string{}
+	for _, key := range append([]string{"DB_CONNECTION", "FORWARD_DB_PORT"}, dbConnectionKeys...) {
+		value, _, _ := envManager.GetVar(key)
+		values[key] = utils.UnquoteValue(value)
+	}
+	return values
+}
+
+// openProjectDatabase connects to the database configured in .env. Hosts that
+// only resolve inside Sail or Compose, like "mysql", are reached through the
+// port forwarded to 127.0.0.1.
+func openProjectDatabase() (*projectDatabase, error) {
+	values := databaseSettings(utils.NewEnvManager(".env"))
+
+	switch connection := values["DB_CONNECTION"]; connection {
+	case "sqlite":
+		path := values["DB_DATABASE"]
+		if path == "" {
+			path = sqliteDatabasePath
+		}
+		if path == ":memory:" {
+			return nil, fmt.Errorf("DB_DATABASE is :memory:, there is nothing to query")
+		}
+		if !fileExists(path) {
+			return nil, fmt.Errorf("sqlite database %s not found", path)
+		}
+		if _, err := exec.LookPath("sqlite3"); err != nil {
+			return nil, fmt.Errorf("reading sqlite databases needs the sqlite3 CLI")
+		}
+		return &projectDatabase{sqlitePath: path}, nil
+	case "mysql", "mariadb":
+		host, port := values["DB_HOST"], values["DB_PORT"]
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		if port == "" {
+			port = "3306"
+		}
+		if _, err := net.LookupHost(host); err != nil {
+			host = "127.0.0.1"
+			if values["FORWARD_DB_PORT"] != "" {
+				port = values["FORWARD_DB_PORT"]
+			}
+		}
+
+		dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?timeout=5s", values["DB_USERNAME"], values["DB_PASSWORD"], net.JoinHostPort(host, port), values["DB_DATABASE"])
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("error opening database: %v", err)
+		}
+		if err := db.Ping(); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error connecting to %s: %v", values["DB_DATABASE"], err)
+		}
+		return &projectDatabase{db: db}, nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_CONNECTION %q (supported: mysql, mariadb, sqlite)", connection)
+	}
+}
+
+func (d *projectDatabase) Close() {
+	if d.db != nil {
+		d.db.Close()
+	}
+}
+
+// query returns every row as a map from column name to its text value.
+// NULL becomes an empty string.
+func (d *projectDatabase) query(query string, args ...interface{}) ([]map[string]string, error) {
+	if d.db == nil {
+		return d.querySqlite(query, args...)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := map[string]string{}
+		for i, column := range columns {
+			row[column] = values[i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// querySqlite runs the query with "sqlite3 -json". Arguments are inlined as
+// quoted strings since the CLI has no placeholders.
+func (d *projectDatabase) querySqlite(query string, args ...interface{}) ([]map[string]string, error) {
+	if len(args) > 0 {
+		parts := strings.Split(query, "?")
+		if len(parts) != len(args)+1 {
+			return nil, fmt.Errorf("query has %d placeholders but %d arguments", len(parts)-1, len(args))
+		}
+		var inlined strings.Builder
+		for i, arg := range args {
+			inlined.WriteString(parts[i])
+			inlined.WriteString("'" + strings.ReplaceAll(fmt.Sprint(arg), "'", "''") + "'")
+		}
+		inlined.WriteString(parts[len(args)])
+		query = inlined.String()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sqlite3", "-json", "-readonly", d.sqlitePath, query)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
+
+	return parseSqliteJSON(stdout.Bytes())
+}
+
+// parseSqliteJSON converts the output of "sqlite3 -json", which is empty
+// instead of [] when there are no rows
+func parseSqliteJSON(output []byte) ([]map[string]string, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("error parsing sqlite3 output: %v", err)
+	}
+
+	result := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		result[i] = map[string]string{}
+		for column, value := range row {
+			if value != nil {
+				result[i][column] = fmt.Sprint(value)
+			}
+		}
+	}
+	return result, nil
+}
+
+// hasTable reports whether the table exists
+func (d *projectDatabase) hasTable(table string) (bool, error) {
+	query := "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	if d.db == nil {
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+
+	rows, err := d.query(query, table)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
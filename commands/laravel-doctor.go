@@ -0,0 +1,363 @@
+package commands
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is one line of the l:doctor report. Fix tells the user what to do.
+type doctorCheck struct {
+	Name    string
+	Status  doctorStatus
+	Message string
+	Fix     string
+}
+
+var pendingMigrationPattern = regexp.MustCompile(`(?m)(\bPending\s*$|^\|\s*No\s*\|)`)
+
+func LaravelDoctor(cliContext *cli.Context) error {
+	if !fileExists("artisan") {
+		return fmt.Errorf("not a Laravel project")
+	}
+
+	envManager := utils.NewEnvManager(".env")
+	if !fileExists(".env") {
+		return fmt.Errorf("no .env found, run \"mo setup\" first")
+	}
+
+	checks := []doctorCheck{
+		checkLaravelVersion(projectLaravelVersion(), envManager),
+		checkAppKey(envManager),
+		checkAppURL(envManager),
+		checkStorageLink(),
+		checkWritable("storage"),
+		checkWritable("bootstrap/cache"),
+		checkCachedConfig(),
+		checkPHPExtensions(),
+	}
+	checks = append(checks, checkDatabase(envManager)...)
+
+	passed, warnings, failed := 0, 0, 0
+	fmt.Println("----------------------------")
+	for _, check := range checks {
+		symbol := "✓"
+		switch check.Status {
+		case doctorPass:
+			passed++
+		case doctorWarn:
+			symbol = "!"
+			warnings++
+		case doctorFail:
+			symbol = "✗"
+			failed++
+		}
+
+		fmt.Printf("%s %-18s %s\n", symbol, check.Name, check.Message)
+		if check.Fix != "" && check.Status != doctorPass {
+			fmt.Printf("  %-18s → %s\n", "", check.Fix)
+		}
+	}
+	fmt.Println("----------------------------")
+	fmt.Printf("%d passed, %d warning(s), %d failed\n", passed, warnings, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkAppKey(envManager *utils.EnvManager) doctorCheck {
+	check := doctorCheck{Name: "APP_KEY", Fix: "run \"mo env:key --force\""}
+
+	value, _, err := envManager.GetVar("APP_KEY")
+	if err != nil {
+		return doctorFail.with(check, fmt.Sprintf("cannot read .env: %v", err))
+	}
+	value = utils.UnquoteValue(value)
+	if value == "" {
+		check.Fix = "run \"mo env:key\""
+		return doctorFail.with(check, "missing")
+	}
+
+	cipherName := appCipher()
+	key, err := utils.ParseKey(value)
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+	length, err := utils.CipherKeyLength(cipherName)
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+	if len(key) != length {
+		return doctorFail.with(check, fmt.Sprintf("%d bytes, %s needs %d", len(key), cipherName, length))
+	}
+	return doctorPass.with(check, fmt.Sprintf("valid for %s", cipherName))
+}
+
+func checkAppURL(envManager *utils.EnvManager) doctorCheck {
+	check := doctorCheck{Name: "APP_URL", Fix: "set APP_URL to the URL you open the app with, e.g. http://shop.test"}
+
+	value, _, err := envManager.GetVar("APP_URL")
+	if err != nil {
+		return doctorFail.with(check, fmt.Sprintf("cannot read .env: %v", err))
+	}
+	value = utils.UnquoteValue(value)
+
+	switch {
+	case value == "":
+		return doctorWarn.with(check, "not set, generated links will point to http://localhost")
+	case !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://"):
+		return doctorFail.with(check, fmt.Sprintf("%s has no http:// or https:// scheme", value))
+	case strings.HasSuffix(value, "/"):
+		check.Fix = "remove the trailing slash"
+		return doctorWarn.with(check, fmt.Sprintf("%s ends with a slash, generated URLs get a double slash", value))
+	}
+	return doctorPass.with(check, value)
+}
+
+func checkStorageLink() doctorCheck {
+	check := doctorCheck{Name: "public/storage", Fix: "run \"php artisan storage:link\""}
+
+	info, err := os.Lstat("public/storage")
+	if os.IsNotExist(err) {
+		return doctorWarn.with(check, "symlink missing, uploaded files are not public")
+	}
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		check.Fix = "remove public/storage and run \"php artisan storage:link\""
+		return doctorWarn.with(check, "is a directory, not a symlink")
+	}
+	if _, err := os.Stat("public/storage"); err != nil {
+		target, _ := os.Readlink("public/storage")
+		check.Fix = "run \"php artisan storage:link --force\""
+		return doctorFail.with(check, fmt.Sprintf("broken symlink to %s", target))
+	}
+	return doctorPass.with(check, "symlink ok")
+}
+
+func checkWritable(dir string) doctorCheck {
+	check := doctorCheck{Name: dir, Fix: fmt.Sprintf("run \"chmod -R ug+rwx %s\" (and check the owner)", dir)}
+
+	if !fileExists(dir) {
+		check.Fix = fmt.Sprintf("run \"mkdir -p %s\"", dir)
+		return doctorFail.with(check, "missing")
+	}
+
+	file, err := os.CreateTemp(dir, ".mo-doctor-*")
+	if err != nil {
+		return doctorFail.with(check, "not writable")
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return doctorPass.with(check, "writable")
+}
+
+func checkCachedConfig() doctorCheck {
+	check := doctorCheck{Name: "config cache", Fix: "run \"mo l:clear\" (or \"mo l:cache\" to rebuild it)"}
+
+	cached, err := os.Stat("bootstrap/cache/config.php")
+	if os.IsNotExist(err) {
+		return doctorPass.with(check, "not cached, .env is read on every request")
+	}
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+
+	env, err := os.Stat(".env")
+	if err == nil && env.ModTime().After(cached.ModTime()) {
+		return doctorFail.with(check, "stale, .env changed after the config was cached")
+	}
+	return doctorWarn.with(check, "cached, changes to .env are ignored until it is cleared")
+}
+
+func checkPHPExtensions() doctorCheck {
+	check := doctorCheck{Name: "PHP extensions"}
+
+	required, err := requiredPHPExtensions("composer.json")
+	if err != nil {
+		return doctorWarn.with(check, err.Error())
+	}
+	if len(required) == 0 {
+		return doctorPass.with(check, "none required by composer.json")
+	}
+
+	cmd, err := utils.CapturedProjectCommand("php", "-m")
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = nil
+	if err := cmd.Run(); err != nil {
+		check.Fix = "install PHP or check the runtime (mo --host ...)"
+		return doctorFail.with(check, fmt.Sprintf("cannot run php -m: %v", err))
+	}
+
+	missing := missingPHPExtensions(required, output.String())
+	if len(missing) > 0 {
+		check.Fix = fmt.Sprintf("install or enable %s", strings.Join(missing, ", "))
+		return doctorFail.with(check, fmt.Sprintf("missing %s", strings.Join(missing, ", ")))
+	}
+	return doctorPass.with(check, fmt.Sprintf("%s loaded", strings.Join(required, ", ")))
+}
+
+// requiredPHPExtensions returns the ext-* packages of the require section
+func requiredPHPExtensions(composerPath string) ([]string, error) {
+	data, err := os.ReadFile(composerPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s", composerPath)
+	}
+
+	var composer struct {
+		Require map[string]string `json:"require"`
+	}
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", composerPath, err)
+	}
+
+	var extensions []string
+	for name := range composer.Require {
+		if strings.HasPrefix(name, "ext-") {
+			extensions = append(extensions, strings.ToLower(strings.TrimPrefix(name, "ext-")))
+		}
+	}
+	sort.Strings(extensions)
+	return extensions, nil
+}
+
+// missingPHPExtensions compares required extensions with the output of php -m
+func missingPHPExtensions(required []string, phpModules string) []string {
+	loaded := map[string]bool{}
+	for _, line := range strings.Split(phpModules, "\n") {
+		name := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(line), " ", "-"))
+		loaded[name] = true
+		loaded[strings.TrimPrefix(name, "zend-")] = true
+	}
+
+	var missing []string
+	for _, extension := range required {
+		if !loaded[extension] {
+			missing = append(missing, extension)
+		}
+	}
+	return missing
+}
+
+// checkDatabase connects with the .env settings and looks for pending migrations
+func checkDatabase(envManager *utils.EnvManager) []doctorCheck {
+	check := doctorCheck{Name: "database"}
+	values := databaseSettings(envManager)
+
+	connection := values["DB_CONNECTION"]
+	runtime, _ := utils.ProjectRuntime()
+
+	switch {
+	case connection == "sqlite":
+		path := values["DB_DATABASE"]
+		if path == "" {
+			path = sqliteDatabasePath
+		}
+		if path != ":memory:" && !fileExists(path) {
+			check.Fix = "run \"mo env:db sqlite\" to create it"
+			return []doctorCheck{doctorFail.with(check, fmt.Sprintf("sqlite file %s missing", path))}
+		}
+	case runtime != utils.RuntimeHost:
+		// The host can't resolve container names like "mysql", migrate:status checks the connection
+		return containerDatabaseChecks(check, connection, checkMigrations())
+	case connection == "mysql" || connection == "mariadb":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?timeout=3s", values["DB_USERNAME"], values["DB_PASSWORD"], values["DB_HOST"], values["DB_PORT"], values["DB_DATABASE"])
+		db, err := sql.Open("mysql", dsn)
+		if err == nil {
+			err = db.Ping()
+			db.Close()
+		}
+		if err != nil {
+			check.Fix = "start the database server and check the DB_* values (\"mo db:create\" creates a missing database)"
+			return []doctorCheck{doctorFail.with(check, fmt.Sprintf("cannot connect to %s: %v", values["DB_DATABASE"], err))}
+		}
+	default:
+		address := net.JoinHostPort(values["DB_HOST"], values["DB_PORT"])
+		conn, err := net.DialTimeout("tcp", address, 3*time.Second)
+		if err != nil {
+			check.Fix = "start the database server and check DB_HOST and DB_PORT"
+			return []doctorCheck{doctorFail.with(check, fmt.Sprintf("%s is not reachable", address))}
+		}
+		conn.Close()
+	}
+
+	checks := []doctorCheck{doctorPass.with(check, fmt.Sprintf("%s connection ok", connection))}
+	return append(checks, checkMigrations())
+}
+
+// containerDatabaseChecks derives the connection check from migrate:status run
+// in the container, which only succeeds (or misses the migrations table) once
+// it has connected
+func containerDatabaseChecks(check doctorCheck, connection string, migrations doctorCheck) []doctorCheck {
+	if migrations.Status == doctorFail {
+		check.Fix = migrations.Fix
+		return []doctorCheck{doctorFail.with(check, fmt.Sprintf("%s connection not verified, migrate:status failed in the container", connection))}
+	}
+
+	checks := []doctorCheck{doctorPass.with(check, fmt.Sprintf("%s connection ok (checked via migrate:status in the container)", connection))}
+	return append(checks, migrations)
+}
+
+func checkMigrations() doctorCheck {
+	check := doctorCheck{Name: "migrations"}
+
+	cmd, err := utils.CapturedProjectCommand("php", "artisan", "migrate:status", "--no-ansi")
+	if err != nil {
+		return doctorFail.with(check, err.Error())
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		check.Fix = "run \"php artisan migrate:status\" to see the error"
+		if strings.Contains(output.String(), "Migration table not found") {
+			check.Fix = "run \"php artisan migrate\""
+			return doctorWarn.with(check, "not migrated yet")
+		}
+		return doctorFail.with(check, "migrate:status failed")
+	}
+
+	if pending := countPendingMigrations(output.String()); pending > 0 {
+		check.Fix = "run \"php artisan migrate\""
+		return doctorWarn.with(check, fmt.Sprintf("%d pending", pending))
+	}
+	return doctorPass.with(check, "up to date")
+}
+
+// countPendingMigrations understands the table of Laravel < 9 and the list of newer versions
+func countPendingMigrations(output string) int {
+	return len(pendingMigrationPattern.FindAllString(output, -1))
+}
+
+// with returns the check with the status and message set
+func (s doctorStatus) with(check doctorCheck, message string) doctorCheck {
+	check.Status = s
+	check.Message = message
+	return check
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mo/utils"
+)
+
+func TestCheckAppKeyAndURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		wantKey    doctorStatus
+		wantURL    doctorStatus
+		keyMessage string
+	}{
+		{
+			name:    "valid",
+			env:     "APP_KEY=base64:Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE=\nAPP_URL=http://shop.test\n",
+			wantKey: doctorPass,
+			wantURL: doctorPass,
+		},
+		{
+			name:       "missing key and url",
+			env:        "APP_KEY=\n",
+			wantKey:    doctorFail,
+			wantURL:    doctorWarn,
+			keyMessage: "missing",
+		},
+		{
+			name:       "short key and no scheme",
+			env:        "APP_KEY=base64:c2hvcnQ=\nAPP_URL=shop.test\n",
+			wantKey:    doctorFail,
+			wantURL:    doctorFail,
+			keyMessage: "5 bytes",
+		},
+		{
+			name:    "trailing slash",
+			env:     "APP_KEY=\"base64:Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE=\"\nAPP_URL=https://shop.test/\n",
+			wantKey: doctorPass,
+			wantURL: doctorWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envPath := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(envPath, []byte(tt.env), 0644); err != nil {
+				t.Fatal(err)
+			}
+			envManager := utils.NewEnvManager(envPath)
+
+			key := checkAppKey(envManager)
+			if key.Status != tt.wantKey || !strings.Contains(key.Message, tt.keyMessage) {
+				t.Errorf("checkAppKey() = %+v, want status %d with %q", key, tt.wantKey, tt.keyMessage)
+			}
+			if url := checkAppURL(envManager); url.Status != tt.wantURL {
+				t.Errorf("checkAppURL() = %+v, want status %d", url, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestFilesystemChecks(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"storage/app/public", "bootstrap/cache", "public"} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if check := checkStorageLink(); check.Status != doctorWarn {
+		t.Errorf("missing link: %+v", check)
+	}
+	if err := os.Symlink(filepath.Join(dir, "storage/app/public"), "public/storage"); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkStorageLink(); check.Status != doctorPass {
+		t.Errorf("valid link: %+v", check)
+	}
+
+	if check := checkWritable("bootstrap/cache"); check.Status != doctorPass {
+		t.Errorf("writable dir: %+v", check)
+	}
+	if check := checkWritable("missing"); check.Status != doctorFail {
+		t.Errorf("missing dir: %+v", check)
+	}
+
+	if check := checkCachedConfig(); check.Status != doctorPass {
+		t.Errorf("no cached config: %+v", check)
+	}
+	if err := os.WriteFile("bootstrap/cache/config.php", []byte("<?php return [];"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".env", []byte("APP_ENV=local\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes("bootstrap/cache/config.php", past, past); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkCachedConfig(); check.Status != doctorFail {
+		t.Errorf("stale cached config: %+v", check)
+	}
+}
+
+func TestPHPExtensions(t *testing.T) {
+	composerPath := filepath.Join(t.TempDir(), "composer.json")
+	composer := `{"require": {"php": "^8.2", "ext-pdo_mysql": "*", "ext-Intl": "*", "ext-zend-opcache": "*", "laravel/framework": "^11.0"}}`
+	if err := os.WriteFile(composerPath, []byte(composer), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	required, err := requiredPHPExtensions(composerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(required, ",") != "intl,pdo_mysql,zend-opcache" {
+		t.Errorf("required = %v", required)
+	}
+
+	modules := "[PHP Modules]\nCore\npdo_mysql\nZend OPcache\n\n[Zend Modules]\nZend OPcache\n"
+	if missing := missingPHPExtensions(required, modules); strings.Join(missing, ",") != "intl" {
+		t.Errorf("missing = %v, want [intl]", missing)
+	}
+}
+
+func TestCountPendingMigrations(t *testing.T) {
+	modern := `
+  Migration name .............................................. Batch / Status
+  0001_01_01_000000_create_users_table ................................ [1] Ran
+  2024_05_01_120000_create_orders_table ................................ Pending
+  2024_05_02_120000_add_total_to_orders_table .......................... Pending
+`
+	legacy := `+------+------------------------------------------------+-------+
+| Ran? | Migration                                      | Batch |
++------+------------------------------------------------+-------+
+| Yes  | 2014_10_12_000000_create_users_table           | 1     |
+| No   | 2019_08_19_000000_create_failed_jobs_table     |       |
++------+------------------------------------------------+-------+
+`
+	if got := countPendingMigrations(modern); got != 2 {
+		t.Errorf("modern output: got %d, want 2", got)
+	}
+	if got := countPendingMigrations(legacy); got != 1 {
+		t.Errorf("legacy output: got %d, want 1", got)
+	}
+}
+
+func TestContainerDatabaseChecks(t *testing.T) {
+	check := doctorCheck{Name: "database"}
+
+	failed := doctorFail.with(doctorCheck{Name: "migrations", Fix: "run \"php artisan migrate:status\" to see the error"}, "migrate:status failed")
+	checks := containerDatabaseChecks(check, "mysql", failed)
+	if len(checks) != 1 || checks[0].Status != doctorFail || strings.Contains(checks[0].Message, "ok") {
+		t.Errorf("failed migrate:status should fail the connection check, got %+v", checks)
+	}
+	if checks[0].Fix != failed.Fix {
+		t.Errorf("Fix = %q, want the migrate:status fix", checks[0].Fix)
+	}
+
+	for _, migrations := range []doctorCheck{
+		doctorPass.with(doctorCheck{Name: "migrations"}, "up to date"),
+		doctorWarn.with(doctorCheck{Name: "migrations"}, "not migrated yet"),
+	} {
+		checks := containerDatabaseChecks(check, "mysql", migrations)
+		if len(checks) != 2 || checks[0].Status != doctorPass || checks[1] != migrations {
+			t.Errorf("migrations %q: got %+v", migrations.Message, checks)
+		}
+		if !strings.Contains(checks[0].Message, "checked via migrate:status in the container") {
+			t.Errorf("message = %q", checks[0].Message)
+		}
+	}
+}
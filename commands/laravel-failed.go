@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// failedJob is a row of the failed_jobs table. Laravel < 8 has no uuid column.
+type failedJob struct {
+	ID         string
+	UUID       string
+	Connection string
+	Queue      string
+	Payload    string
+	Exception  string
+	FailedAt   string
+}
+
+// serializedClassPattern matches the class of a serialized PHP object
+var serializedClassPattern = regexp.MustCompile(`^O:\d+:"([^"]+)"`)
+
+func LaravelFailed(cliContext *cli.Context) error {
+	db, err := openFailedJobs()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := "SELECT * FROM failed_jobs"
+	var args []interface{}
+	if queue := cliContext.String("queue"); queue != "" {
+		query += " WHERE queue = ?"
+		args = append(args, queue)
+	}
+	query += " ORDER BY id DESC"
+	if limit := cliContext.Int("limit"); limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := db.query(query, args...)
+	if err != nil {
+		return fmt.Errorf("error reading failed_jobs: %v", err)
+	}
+	if len(rows) == 0 {
+		fmt.Println("✓ No failed jobs")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tQUEUE\tFAILED AT\tJOB\tEXCEPTION")
+	for _, row := range rows {
+		job := failedJobFromRow(row)
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", job.identifier(), job.Queue, job.FailedAt, jobClass(job.Payload), truncate(exceptionSummary(job.Exception), 80))
+	}
+	writer.Flush()
+
+	fmt.Println("----------------------------")
+	fmt.Printf("%d failed job(s). Details: mo l:failed show <id>\n", len(rows))
+	return nil
+}
+
+func LaravelFailedShow(cliContext *cli.Context) error {
+	id := cliContext.Args().First()
+	if id == "" {
+		return fmt.Errorf("usage: l:failed show <uuid>")
+	}
+
+	db, err := openFailedJobs()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	column := "uuid"
+	if _, err := strconv.Atoi(id); err == nil {
+		column = "id"
+	}
+	rows, err := db.query("SELECT * FROM failed_jobs WHERE "+column+" = ?", id)
+	if err != nil {
+		return fmt.Errorf("error reading failed_jobs: %v", err)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("failed job %s not found", id)
+	}
+
+	job := failedJobFromRow(rows[0])
+	fmt.Println("----------------------------")
+	fmt.Printf("ID:         %s\n", job.identifier())
+	fmt.Printf("Job:        %s\n", jobClass(job.Payload))
+	fmt.Printf("Connection: %s\n", job.Connection)
+	fmt.Printf("Queue:      %s\n", job.Queue)
+	fmt.Printf("Failed at:  %s\n", job.FailedAt)
+	fmt.Println("----------------------------")
+	fmt.Println("Payload:")
+	fmt.Println(indentPayload(job.Payload))
+	fmt.Println("----------------------------")
+	fmt.Println("Exception:")
+	fmt.Println(strings.TrimRight(job.Exception, "\n"))
+	return nil
+}
+
+func LaravelFailedRetry(cliContext *cli.Context) error {
+	ids := cliContext.Args().Slice()
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: l:failed retry <uuid...|all>")
+	}
+
+	if err := utils.RunProjectCommand("php", append([]string{"artisan", "queue:retry"}, ids...)...); err != nil {
+		return fmt.Errorf("error running queue:retry: %w", err)
+	}
+	return nil
+}
+
+func LaravelFailedForget(cliContext *cli.Context) error {
+	ids := cliContext.Args().Slice()
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: l:failed forget <uuid...>")
+	}
+
+	// queue:forget takes a single id
+	for _, id := range ids {
+		if err := utils.RunProjectCommand("php", "artisan", "queue:forget", id); err != nil {
+			return fmt.Errorf("error running queue:forget %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func openFailedJobs() (*projectDatabase, error) {
+	if !fileExists("artisan") {
+		return nil, fmt.Errorf("not a Laravel project")
+	}
+
+	db, err := openProjectDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	hasTable, err := db.hasTable("failed_jobs")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error reading failed_jobs: %v", err)
+	}
+	if !hasTable {
+		db.Close()
+		return nil, fmt.Errorf("the failed_jobs table doesn't exist, run \"php artisan migrate\"")
+	}
+	return db, nil
+}
+
+func failedJobFromRow(row map[string]string) failedJob {
+	return failedJob{
+		ID:         row["id"],
+		UUID:       row["uuid"],
+		Connection: row["connection"],
+		Queue:      row["queue"],
+		Payload:    row["payload"],
+		Exception:  row["exception"],
+		FailedAt:   row["failed_at"],
+	}
+}
+
+// identifier is what queue:retry and queue:forget expect, the uuid since Laravel 8
+func (j failedJob) identifier() string {
+	if j.UUID != "" {
+		return j.UUID
+	}
+	return j.ID
+}
+
+// jobClass decodes the job class from the queue payload. displayName is set
+// for jobs, listeners and mailables, older payloads only have the serialized
+// command.
+func jobClass(payload string) string {
+	var decoded struct {
+		DisplayName string `json:"displayName"`
+		Job         string `json:"job"`
+		Data        struct {
+			CommandName string `json:"commandName"`
+			Command     string `json:"command"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+		return "?"
+	}
+
+	switch {
+	case decoded.DisplayName != "":
+		return decoded.DisplayName
+	case decoded.Data.CommandName != "":
+		return decoded.Data.CommandName
+	}
+	if match := serializedClassPattern.FindStringSubmatch(decoded.Data.Command); match != nil {
+		return match[1]
+	}
+	if decoded.Job != "" {
+		return decoded.Job
+	}
+	return "?"
+}
+
+// exceptionSummary returns the first line of the stored exception, which
+// holds the class and the message
+func exceptionSummary(exception string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(exception), "\n")
+	return strings.TrimSpace(line)
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+// indentPayload pretty prints the JSON payload, or returns it as stored if it
+// isn't valid JSON
+func indentPayload(payload string) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(payload), "", "  "); err != nil {
+		return payload
+	}
+	return indented.String()
+}
@@ -0,0 +1,61 @@
+package commands
+
+import "testing"
+
+func TestJobClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name:    "display name",
+			payload: `{"uuid":"9a1","displayName":"App\\Jobs\\SendInvoice","job":"Illuminate\\Queue\\CallQueuedHandler@call","data":{"commandName":"App\\Jobs\\SendInvoice","command":"O:20:\"App\\Jobs\\SendInvoice\":1:{}"}}`,
+			want:    `App\Jobs\SendInvoice`,
+		},
+		{
+			name:    "command name",
+			payload: `{"job":"Illuminate\\Queue\\CallQueuedHandler@call","data":{"commandName":"App\\Jobs\\ImportCsv"}}`,
+			want:    `App\Jobs\ImportCsv`,
+		},
+		{
+			name:    "serialized command",
+			payload: `{"job":"Illuminate\\Queue\\CallQueuedHandler@call","data":{"command":"O:25:\"App\\Listeners\\SyncStripe\":2:{s:5:\"tries\";i:3;}"}}`,
+			want:    `App\Listeners\SyncStripe`,
+		},
+		{
+			name:    "plain job",
+			payload: `{"job":"App\\Jobs\\LegacyHandler@fire","data":{}}`,
+			want:    `App\Jobs\LegacyHandler@fire`,
+		},
+		{name: "invalid json", payload: `not json`, want: "?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jobClass(tt.payload); got != tt.want {
+				t.Errorf("jobClass() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceptionSummary(t *testing.T) {
+	exception := "\nRuntimeException: Stripe is down in /app/app/Jobs/SyncStripe.php:42\nStack trace:\n#0 /app/vendor/laravel/framework/src/Illuminate/Container/BoundMethod.php(36): App\\Jobs\\SyncStripe->handle()\n"
+	if got := exceptionSummary(exception); got != "RuntimeException: Stripe is down in /app/app/Jobs/SyncStripe.php:42" {
+		t.Errorf("exceptionSummary() = %q", got)
+	}
+
+	if got := truncate("RuntimeException: Stripe is down", 10); got != "RuntimeEx…" {
+		t.Errorf("truncate() = %q", got)
+	}
+}
+
+func TestFailedJobIdentifier(t *testing.T) {
+	if id := failedJobFromRow(map[string]string{"id": "7", "uuid": "9b2c"}).identifier(); id != "9b2c" {
+		t.Errorf("identifier() = %q, want the uuid", id)
+	}
+	if id := failedJobFromRow(map[string]string{"id": "7"}).identifier(); id != "7" {
+		t.Errorf("identifier() = %q, want the id without a uuid column", id)
+	}
+}
@@ -12,6 +12,22 @@ func LaravelFresh(cliContext *cli.Context) error {
 		return fmt.Errorf("not a Laravel project")
 	}
 
+	action := "migrate:fresh"
+	if cliContext.Bool("wipe") {
+		action = "db:wipe"
+	}
+	if err := guardDestructive(action, cliContext.Bool("force")); err != nil {
+		return err
+	}
+
+	if err := ensureFreshSqliteDatabase(); err != nil {
+		return err
+	}
+
+	if cliContext.Bool("wipe") {
+		return wipeDatabase(projectLaravelVersion())
+	}
+
 	args := []string{"artisan", "migrate:fresh"}
 
 	if !cliContext.Bool("no-seed") {
@@ -21,10 +37,39 @@ func LaravelFresh(cliContext *cli.Context) error {
 		fmt.Println("Running migrate:fresh...")
 	}
 
-	if err := utils.RunCommand("php", args...); err != nil {
+	if err := utils.RunProjectCommand("php", args...); err != nil {
 		return fmt.Errorf("error running migrate:fresh: %w", err)
 	}
 
 	fmt.Println("✓ Database refreshed successfully!")
 	return nil
 }
+
+// wipeDatabase drops all tables without migrating. db:wipe exists since Laravel 5.6.
+func wipeDatabase(version laravelVersion) error {
+	if version.known() && (version.Major < 5 || (version.Major == 5 && version.Minor < 6)) {
+		return fmt.Errorf("db:wipe needs Laravel 5.6 or newer, this project uses %s", version.Version)
+	}
+
+	fmt.Println("Running db:wipe...")
+	if err := utils.RunProjectCommand("php", "artisan", "db:wipe"); err != nil {
+		return fmt.Errorf("error running db:wipe: %w", err)
+	}
+
+	fmt.Println("✓ Database wiped!")
+	return nil
+}
+
+// ensureFreshSqliteDatabase creates database/database.sqlite, the sqlite default
+// since Laravel 11, so migrate:fresh doesn't stop to ask for it
+func ensureFreshSqliteDatabase() error {
+	envManager := utils.NewEnvManager(".env")
+	connection, _, err := envManager.GetVar("DB_CONNECTION")
+	if err != nil || utils.UnquoteValue(connection) != "sqlite" {
+		return nil
+	}
+	if database, _, _ := envManager.GetVar("DB_DATABASE"); utils.UnquoteValue(database) != "" {
+		return nil
+	}
+	return ensureSqliteDatabase()
+}
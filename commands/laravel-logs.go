@@ -0,0 +1,436 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const laravelLogDir = "storage/logs"
+
+// logLevels are Monolog's levels from least to most severe
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+var logHeaderPattern = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:[+-]\d{2}:?\d{2}|Z)?)\] ([^\s.]+(?:\.[^\s.]+)*)\.([A-Z]+): (.*)$`)
+
+var logStackFramePattern = regexp.MustCompile(`^#\d+ `)
+
+var logExceptionPattern = regexp.MustCompile(`\[object\] \(([^(]+)\(code: [^)]*\): (.*) at (\S+:\d+)\)`)
+
+var dailyLogPattern = regexp.MustCompile(`^laravel-(\d{4}-\d{2}-\d{2})\.log$`)
+
+// logEntry is a single parsed Monolog record
+type logEntry struct {
+	Time      time.Time       `json:"time"`
+	Env       string          `json:"env"`
+	Level     string          `json:"level"`
+	Message   string          `json:"message"`
+	Context   json.RawMessage `json:"context,omitempty"`
+	Exception string          `json:"exception,omitempty"`
+	Stack     []string        `json:"stack,omitempty"`
+	File      string          `json:"file"`
+}
+
+// logFilter holds the --level, --since and --grep options
+type logFilter struct {
+	minLevel int
+	since    time.Time
+	grep     *regexp.Regexp
+}
+
+func LaravelLogs(cliContext *cli.Context) error {
+	filter, err := newLogFilter(cliContext.String("level"), cliContext.String("since"), cliContext.String("grep"))
+	if err != nil {
+		return err
+	}
+
+	files, err := laravelLogFiles(cliContext.Args().Slice(), filter.since)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no log files found in %s", laravelLogDir)
+	}
+
+	var entries []logEntry
+	for _, file := range files {
+		parsed, err := readLogFile(file)
+		if err != nil {
+			return err
+		}
+		for _, entry := range parsed {
+			if filter.matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	if limit := cliContext.Int("lines"); limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	show := func(entry logEntry) {
+		printLogEntry(entry, cliContext.Bool("json"), cliContext.Bool("trace"))
+	}
+	for _, entry := range entries {
+		show(entry)
+	}
+
+	if cliContext.Bool("follow") {
+		return followLogs(cliContext.Args().Slice(), files[len(files)-1], filter, show)
+	}
+	return nil
+}
+
+func newLogFilter(level, since, grep string) (logFilter, error) {
+	var filter logFilter
+
+	if level != "" {
+		filter.minLevel = logLevelRank(level)
+		if filter.minLevel < 0 {
+			return filter, fmt.Errorf("unknown level %q (use one of %s)", level, strings.Join(logLevels, ", "))
+		}
+	}
+
+	if since != "" {
+		parsed, err := parseSince(since, time.Now())
+		if err != nil {
+			return filter, err
+		}
+		filter.since = parsed
+	}
+
+	if grep != "" {
+		pattern, err := regexp.Compile("(?i)" + grep)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --grep pattern: %v", err)
+		}
+		filter.grep = pattern
+	}
+
+	return filter, nil
+}
+
+func (f logFilter) matches(entry logEntry) bool {
+	if logLevelRank(entry.Level) < f.minLevel {
+		return false
+	}
+	if !f.since.IsZero() && entry.Time.Before(f.since) {
+		return false
+	}
+	if f.grep != nil && !f.grep.MatchString(entry.Message) && !f.grep.Match(entry.Context) && !f.grep.MatchString(entry.Exception) {
+		return false
+	}
+	return true
+}
+
+func logLevelRank(level string) int {
+	for i, name := range logLevels {
+		if strings.EqualFold(name, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseSince accepts a duration ("2h", "30m"), a date, a date with time or a
+// time of today
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return parsed, nil
+		}
+	}
+
+	if parsed, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location()), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 2h, 2024-05-01 or \"2024-05-01 14:00\")", value)
+}
+
+// laravelLogFiles returns the files to read, oldest first. Without explicit
+// files the newest log is used, or all logs since the given time.
+func laravelLogFiles(args []string, since time.Time) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(laravelLogDir, "laravel*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []logFile
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if match := dailyLogPattern.FindStringSubmatch(filepath.Base(path)); match != nil && !since.IsZero() {
+			day, err := time.ParseInLocation("2006-01-02", match[1], since.Location())
+			if err == nil && day.AddDate(0, 0, 1).Before(since) {
+				continue
+			}
+		}
+		files = append(files, logFile{path, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	if len(files) > 0 && since.IsZero() {
+		files = files[len(files)-1:]
+	}
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+	return paths, nil
+}
+
+func readLogFile(path string) ([]logEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	defer file.Close()
+
+	entries, err := parseLogEntries(file, path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	return entries, nil
+}
+
+func parseLogEntries(r io.Reader, path string) ([]logEntry, error) {
+	parser := &logParser{file: path}
+	var entries []logEntry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if entry := parser.add(scanner.Text()); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	if entry := parser.flush(); entry != nil {
+		entries = append(entries, *entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// logParser groups lines into entries. An entry is complete once the next
+// header line arrives or flush is called.
+type logParser struct {
+	file  string
+	lines []string
+}
+
+func (p *logParser) add(line string) *logEntry {
+	if logHeaderPattern.MatchString(line) {
+		finished := p.flush()
+		p.lines = []string{line}
+		return finished
+	}
+	if len(p.lines) > 0 {
+		p.lines = append(p.lines, line)
+	}
+	return nil
+}
+
+func (p *logParser) flush() *logEntry {
+	if len(p.lines) == 0 {
+		return nil
+	}
+	entry := buildLogEntry(p.lines, p.file)
+	p.lines = nil
+	return &entry
+}
+
+func buildLogEntry(lines []string, file string) logEntry {
+	header := logHeaderPattern.FindStringSubmatch(lines[0])
+	entry := logEntry{
+		Env:   header[2],
+		Level: strings.ToLower(header[3]),
+		File:  file,
+	}
+	entry.Time = parseLogTime(header[1])
+
+	body := header[4]
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case logStackFramePattern.MatchString(trimmed), strings.HasPrefix(trimmed, "[previous exception]"):
+			entry.Stack = append(entry.Stack, trimmed)
+		case trimmed == "[stacktrace]", trimmed == "", strings.HasPrefix(trimmed, `"}`):
+		default:
+			body += "\n" + line
+		}
+	}
+
+	var rawContext string
+	entry.Message, rawContext = splitLogContext(body, len(entry.Stack) > 0)
+
+	if match := logExceptionPattern.FindStringSubmatch(rawContext); match != nil {
+		entry.Exception = fmt.Sprintf("%s: %s at %s", match[1], match[2], match[3])
+	}
+
+	switch {
+	case rawContext == "", rawContext == "[]", rawContext == "{}":
+	case json.Valid([]byte(rawContext)):
+		entry.Context = json.RawMessage(rawContext)
+	case entry.Exception == "":
+		// Keep broken or truncated context as a JSON string
+		encoded, _ := json.Marshal(rawContext)
+		entry.Context = encoded
+	}
+
+	return entry
+}
+
+func parseLogTime(value string) time.Time {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed
+	}
+	parsed, _ := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	return parsed
+}
+
+// splitLogContext separates the message from the JSON context and the empty
+// extra ("[]") Monolog appends. Exception contexts are cut by the stack trace
+// and never valid JSON, so they are split at the first ` {"`.
+func splitLogContext(rest string, hasStack bool) (string, string) {
+	rest = strings.TrimSuffix(rest, " []")
+
+	for i := 0; i < len(rest); i++ {
+		if (rest[i] != ' ' && rest[i] != '\n') || i+1 >= len(rest) || (rest[i+1] != '{' && rest[i+1] != '[') {
+			continue
+		}
+		if json.Valid([]byte(rest[i+1:])) {
+			return rest[:i], rest[i+1:]
+		}
+	}
+
+	if hasStack {
+		if index := strings.Index(rest, ` {"`); index >= 0 {
+			return rest[:index], rest[index+1:]
+		}
+	}
+	return rest, ""
+}
+
+func printLogEntry(entry logEntry, asJSON, trace bool) {
+	if asJSON {
+		data, err := json.Marshal(entry)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	fmt.Printf("%s %s.%-9s %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Env, strings.ToUpper(entry.Level), entry.Message)
+	if len(entry.Context) > 0 {
+		fmt.Printf("    %s\n", entry.Context)
+	}
+	if entry.Exception != "" {
+		fmt.Printf("    %s\n", entry.Exception)
+	}
+	if len(entry.Stack) == 0 {
+		return
+	}
+	if trace {
+		for _, frame := range entry.Stack {
+			fmt.Printf("      %s\n", frame)
+		}
+	} else {
+		fmt.Printf("    [%d stack frames, use --trace to show them]\n", len(entry.Stack))
+	}
+}
+
+// followLogs prints entries appended to the newest log file, switching to a
+// new daily file when one appears
+func followLogs(args []string, path string, filter logFilter, show func(logEntry)) error {
+	offset := int64(0)
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	parser := &logParser{file: path}
+	partial := ""
+
+	for {
+		time.Sleep(500 * time.Millisecond)
+
+		if len(args) == 0 {
+			if files, err := laravelLogFiles(nil, time.Time{}); err == nil && len(files) > 0 && files[0] != path {
+				if entry := parser.flush(); entry != nil && filter.matches(*entry) {
+					show(*entry)
+				}
+				path, offset, partial = files[0], 0, ""
+				parser = &logParser{file: path}
+			}
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() < offset {
+			// The file was truncated or rotated
+			offset, partial = 0, ""
+		}
+		if info.Size() == offset {
+			// Nothing new, so the pending entry is complete
+			if entry := parser.flush(); entry != nil && filter.matches(*entry) {
+				show(*entry)
+			}
+			continue
+		}
+
+		data, err := readFrom(path, offset)
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+
+		lines := strings.Split(partial+string(data), "\n")
+		partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			if entry := parser.add(line); entry != nil && filter.matches(*entry) {
+				show(*entry)
+			}
+		}
+	}
+}
+
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
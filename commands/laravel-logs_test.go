@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleLaravelLog = `[2024-05-01 12:00:00] local.INFO: User logged in {"user_id":1} []
+[2024-05-01 12:01:00] local.DEBUG: Cache miss [] []
+[2024-05-01 12:02:00] production.ERROR: Undefined variable $order {"userId":1,"exception":"[object] (ErrorException(code: 0): Undefined variable $order at /app/app/Http/Controllers/OrderController.php:42)
+[stacktrace]
+#0 /app/vendor/laravel/framework/src/Illuminate/Foundation/Bootstrap/HandleExceptions.php(255): Illuminate\\Foundation\\Bootstrap\\HandleExceptions->handleError()
+#1 {main}
+"} 
+[2024-05-01T12:03:00.123456+00:00] testing.WARNING: Slow query
+took 1200ms [] []
+`
+
+func TestParseLogEntries(t *testing.T) {
+	entries, err := parseLogEntries(strings.NewReader(sampleLaravelLog), "laravel.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+
+	info := entries[0]
+	if info.Env != "local" || info.Level != "info" || info.Message != "User logged in" || string(info.Context) != `{"user_id":1}` {
+		t.Errorf("info entry = %+v", info)
+	}
+
+	if debug := entries[1]; debug.Message != "Cache miss" || len(debug.Context) != 0 {
+		t.Errorf("debug entry = %+v", debug)
+	}
+
+	failure := entries[2]
+	if failure.Message != "Undefined variable $order" {
+		t.Errorf("message = %q", failure.Message)
+	}
+	if failure.Exception != "ErrorException: Undefined variable $order at /app/app/Http/Controllers/OrderController.php:42" {
+		t.Errorf("exception = %q", failure.Exception)
+	}
+	if len(failure.Stack) != 2 {
+		t.Errorf("stack = %v, want 2 frames", failure.Stack)
+	}
+
+	warning := entries[3]
+	if warning.Message != "Slow query\ntook 1200ms" {
+		t.Errorf("multiline message = %q", warning.Message)
+	}
+	if warning.Time.UTC().Format("15:04:05") != "12:03:00" {
+		t.Errorf("time = %v", warning.Time)
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	entries, err := parseLogEntries(strings.NewReader(sampleLaravelLog), "laravel.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		level string
+		since string
+		grep  string
+		want  int
+	}{
+		{name: "no filter", want: 4},
+		{name: "warning and above", level: "warning", want: 2},
+		{name: "grep context", grep: "user_id", want: 1},
+		{name: "grep exception", grep: "ordercontroller", want: 1},
+		{name: "since", since: "2024-05-01 12:01:30", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogFilter(tt.level, tt.since, tt.grep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !filter.since.IsZero() {
+				// The sample log has no timezone for most entries, compare in local time
+				filter.since, _ = time.ParseInLocation("2006-01-02 15:04:05", tt.since, time.Local)
+			}
+
+			got := 0
+			for _, entry := range entries {
+				if filter.matches(entry) {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("matched %d entries, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2h", want: time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC)},
+		{value: "2024-04-30", want: time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-04-30 08:15", want: time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC)},
+		{value: "09:00", want: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const migrationsDir = "database/migrations"
+
+type migrationState string
+
+const (
+	migrationRan      migrationState = "ran"
+	migrationPending  migrationState = "pending"
+	migrationOrphaned migrationState = "orphaned"
+)
+
+// migrationStatus is a migration file, a row of the migrations table or both
+type migrationStatus struct {
+	Name  string
+	State migrationState
+	Batch int
+	Risks []migrationRisk
+}
+
+// migrationRisk is a destructive operation in the up() method of a migration
+type migrationRisk struct {
+	Line      int
+	Operation string
+}
+
+var upMethodPattern = regexp.MustCompile(`function\s+up\s*\(`)
+
+// destructiveCallPattern matches schema builder calls that drop or rename data
+var destructiveCallPattern = regexp.MustCompile(`(?:->|::)(dropColumns?|renameColumn|dropIfExists|drop|rename|dropMorphs|dropTimestamps(?:Tz)?|dropSoftDeletes(?:Tz)?|dropRememberToken|truncate)\s*\(`)
+
+// destructiveSQLPattern matches the same in raw statements
+var destructiveSQLPattern = regexp.MustCompile(`(?i)\b(drop\s+table|drop\s+column|truncate\s+table)\b`)
+
+func LaravelMigrations(cliContext *cli.Context) error {
+	if !fileExists("artisan") {
+		return fmt.Errorf("not a Laravel project")
+	}
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.php"))
+	if err != nil {
+		return fmt.Errorf("error listing %s: %v", migrationsDir, err)
+	}
+
+	db, err := openProjectDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ran := map[string]int{}
+	hasTable, err := db.hasTable("migrations")
+	if err != nil {
+		return fmt.Errorf("error reading the migrations table: %v", err)
+	}
+	if hasTable {
+		rows, err := db.query("SELECT migration, batch FROM migrations")
+		if err != nil {
+			return fmt.Errorf("error reading the migrations table: %v", err)
+		}
+		for _, row := range rows {
+			ran[row["migration"]], _ = strconv.Atoi(row["batch"])
+		}
+	} else {
+		fmt.Println("The migrations table doesn't exist yet, nothing has been migrated")
+	}
+
+	statuses := compareMigrations(files, ran)
+	for i, status := range statuses {
+		if status.State != migrationPending {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(migrationsDir, status.Name+".php"))
+		if err != nil {
+			return fmt.Errorf("error reading migration %s: %v", status.Name, err)
+		}
+		statuses[i].Risks = destructiveOperations(string(content))
+	}
+
+	printMigrations(statuses, cliContext.Bool("pending"))
+	return nil
+}
+
+// compareMigrations matches the migration files with the rows of the
+// migrations table, sorted by name which starts with the timestamp
+func compareMigrations(files []string, ran map[string]int) []migrationStatus {
+	var statuses []migrationStatus
+	seen := map[string]bool{}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".php")
+		seen[name] = true
+		if batch, ok := ran[name]; ok {
+			statuses = append(statuses, migrationStatus{Name: name, State: migrationRan, Batch: batch})
+		} else {
+			statuses = append(statuses, migrationStatus{Name: name, State: migrationPending})
+		}
+	}
+	for name, batch := range ran {
+		if !seen[name] {
+			statuses = append(statuses, migrationStatus{Name: name, State: migrationOrphaned, Batch: batch})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// destructiveOperations lists the operations in up() that drop or rename
+// tables and columns. down() is skipped, it drops what up() created.
+func destructiveOperations(content string) []migrationRisk {
+	body, firstLine := upMethodBody(content)
+
+	var risks []migrationRisk
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		for _, match := range destructiveCallPattern.FindAllStringSubmatch(line, -1) {
+			risks = append(risks, migrationRisk{Line: firstLine + i, Operation: match[1]})
+		}
+		for _, match := range destructiveSQLPattern.FindAllStringSubmatch(line, -1) {
+			operation := strings.ToUpper(strings.Join(strings.Fields(match[1]), " "))
+			risks = append(risks, migrationRisk{Line: firstLine + i, Operation: operation})
+		}
+	}
+	return risks
+}
+
+// upMethodBody returns the body of the up() method and the line it starts on.
+// Without an up() method the whole file is returned.
+func upMethodBody(content string) (string, int) {
+	loc := upMethodPattern.FindStringIndex(content)
+	if loc == nil {
+		return content, 1
+	}
+
+	open := strings.Index(content[loc[1]:], "{")
+	if open < 0 {
+		return content, 1
+	}
+	start := loc[1] + open
+
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return content[start : i+1], strings.Count(content[:start], "\n") + 1
+			}
+		}
+	}
+	return content[start:], strings.Count(content[:start], "\n") + 1
+}
+
+func printMigrations(statuses []migrationStatus, onlyPending bool) {
+	var ran, pending, destructive, orphaned int
+
+	fmt.Println("----------------------------")
+	for _, status := range statuses {
+		switch status.State {
+		case migrationRan:
+			ran++
+		case migrationPending:
+			pending++
+			if len(status.Risks) > 0 {
+				destructive++
+			}
+		case migrationOrphaned:
+			orphaned++
+		}
+		if onlyPending && status.State == migrationRan {
+			continue
+		}
+
+		batch := ""
+		if status.Batch > 0 {
+			batch = fmt.Sprintf("[%d]", status.Batch)
+		}
+		switch status.State {
+		case migrationRan:
+			fmt.Printf("✓ Ran      %-5s %s\n", batch, status.Name)
+		case migrationPending:
+			fmt.Printf("• Pending  %-5s %s\n", batch, status.Name)
+			for _, risk := range status.Risks {
+				fmt.Printf("    ⚠ %s on line %d\n", risk.Operation, risk.Line)
+			}
+		case migrationOrphaned:
+			fmt.Printf("? Orphaned %-5s %s (no file in %s)\n", batch, status.Name, migrationsDir)
+		}
+	}
+	fmt.Println("----------------------------")
+
+	summary := fmt.Sprintf("%d ran, %d pending", ran, pending)
+	if destructive > 0 {
+		summary += fmt.Sprintf(" (%d destructive)", destructive)
+	}
+	fmt.Printf("%s, %d orphaned\n", summary, orphaned)
+
+	if destructive > 0 {
+		fmt.Println("⚠ Review the destructive migrations before running \"php artisan migrate\" or \"mo push --database\"")
+	}
+}
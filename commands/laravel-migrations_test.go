@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const dropColumnsMigration = `<?php
+
+use Illuminate\Database\Migrations\Migration;
+use Illuminate\Database\Schema\Blueprint;
+use Illuminate\Support\Facades\DB;
+use Illuminate\Support\Facades\Schema;
+
+return new class extends Migration
+{
+    public function up(): void
+    {
+        Schema::table('users', function (Blueprint $table) {
+            $table->dropColumn(['legacy_id', 'notes']);
+            $table->renameColumn('name', 'full_name');
+            // $table->dropColumn('ignored');
+            $table->dropForeign(['team_id']);
+        });
+
+        DB::statement('drop  table old_sessions');
+    }
+
+    public function down(): void
+    {
+        Schema::dropIfExists('users');
+    }
+};
+`
+
+const createTableMigration = `<?php
+
+return new class extends Migration
+{
+    public function up(): void
+    {
+        Schema::create('flights', function (Blueprint $table) {
+            $table->id();
+            $table->timestamps();
+        });
+    }
+
+    public function down(): void
+    {
+        Schema::drop('flights');
+    }
+};
+`
+
+func TestDestructiveOperations(t *testing.T) {
+	risks := destructiveOperations(dropColumnsMigration)
+
+	want := []migrationRisk{
+		{Line: 13, Operation: "dropColumn"},
+		{Line: 14, Operation: "renameColumn"},
+		{Line: 19, Operation: "DROP TABLE"},
+	}
+	if len(risks) != len(want) {
+		t.Fatalf("got %+v, want %+v", risks, want)
+	}
+	for i := range want {
+		if risks[i] != want[i] {
+			t.Errorf("risk %d = %+v, want %+v", i, risks[i], want[i])
+		}
+	}
+
+	if risks := destructiveOperations(createTableMigration); len(risks) != 0 {
+		t.Errorf("create migration flagged: %+v", risks)
+	}
+}
+
+func TestCompareMigrations(t *testing.T) {
+	files := []string{
+		"database/migrations/2014_10_12_000000_create_users_table.php",
+		"database/migrations/2024_05_01_000000_drop_legacy_columns.php",
+		"database/migrations/2019_08_19_000000_create_failed_jobs_table.php",
+	}
+	ran := map[string]int{
+		"2014_10_12_000000_create_users_table":       1,
+		"2019_08_19_000000_create_failed_jobs_table": 1,
+		"2023_01_01_000000_create_teams_table":       2,
+	}
+
+	statuses := compareMigrations(files, ran)
+
+	want := []migrationStatus{
+		{Name: "2014_10_12_000000_create_users_table", State: migrationRan, Batch: 1},
+		{Name: "2019_08_19_000000_create_failed_jobs_table", State: migrationRan, Batch: 1},
+		{Name: "2023_01_01_000000_create_teams_table", State: migrationOrphaned, Batch: 2},
+		{Name: "2024_05_01_000000_drop_legacy_columns", State: migrationPending},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %+v, want %+v", statuses, want)
+	}
+	for i := range want {
+		got := statuses[i]
+		if got.Name != want[i].Name || got.State != want[i].State || got.Batch != want[i].Batch {
+			t.Errorf("status %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestProjectDatabaseSqlite(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 CLI not installed")
+	}
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("database", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".env", []byte("DB_CONNECTION=sqlite\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup := "CREATE TABLE migrations (id INTEGER PRIMARY KEY, migration TEXT, batch INTEGER);" +
+		"INSERT INTO migrations (migration, batch) VALUES ('2014_create_users', 1), ('it''s ? odd', 2);"
+	if output, err := exec.Command("sqlite3", filepath.Join("database", "database.sqlite"), setup).CombinedOutput(); err != nil {
+		t.Fatalf("error creating database: %v: %s", err, output)
+	}
+
+	db, err := openProjectDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if ok, err := db.hasTable("migrations"); err != nil || !ok {
+		t.Fatalf("hasTable(migrations) = %v, %v", ok, err)
+	}
+	if ok, err := db.hasTable("failed_jobs"); err != nil || ok {
+		t.Fatalf("hasTable(failed_jobs) = %v, %v", ok, err)
+	}
+
+	rows, err := db.query("SELECT migration, batch FROM migrations WHERE migration = ?", "it's ? odd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0]["batch"] != "2" {
+		t.Errorf("rows = %v", rows)
+	}
+
+	rows, err = db.query("SELECT migration FROM migrations WHERE batch > 5")
+	if err != nil || len(rows) != 0 {
+		t.Errorf("empty query = %v, %v", rows, err)
+	}
+}
@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"mo/config"
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// laravelRoute is an entry of "php artisan route:list --json"
+type laravelRoute struct {
+	Domain     string          `json:"domain"`
+	Method     string          `json:"method"`
+	URI        string          `json:"uri"`
+	Name       string          `json:"name"`
+	Action     string          `json:"action"`
+	Middleware json.RawMessage `json:"middleware"`
+}
+
+// middleware is a list in recent Laravel versions and a newline separated string in older ones
+func (r laravelRoute) middleware() []string {
+	var list []string
+	if err := json.Unmarshal(r.Middleware, &list); err == nil {
+		return list
+	}
+	var joined string
+	if err := json.Unmarshal(r.Middleware, &joined); err == nil && joined != "" {
+		return strings.Split(joined, "\n")
+	}
+	return nil
+}
+
+func LaravelRoutes(cliContext *cli.Context) error {
+	if !fileExists("artisan") {
+		return fmt.Errorf("not a Laravel project")
+	}
+
+	routes, err := loadLaravelRoutes()
+	if err != nil {
+		return err
+	}
+
+	routes = filterRoutes(routes, strings.Join(cliContext.Args().Slice(), " "), cliContext.String("method"))
+	if len(routes) == 0 {
+		fmt.Println("No matching routes.")
+		return nil
+	}
+
+	if cliContext.Bool("open") {
+		return openRouteAction(routes[0])
+	}
+
+	printRoutes(routes)
+	return nil
+}
+
+func loadLaravelRoutes() ([]laravelRoute, error) {
+	cmd, err := utils.CapturedProjectCommand("php", "artisan", "route:list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running route:list: %w", err)
+	}
+
+	var routes []laravelRoute
+	if err := json.Unmarshal(output.Bytes(), &routes); err != nil {
+		return nil, fmt.Errorf("error parsing route:list output: %v", err)
+	}
+	return routes, nil
+}
+
+// filterRoutes keeps the routes matching the method and the fuzzy query, best matches first
+func filterRoutes(routes []laravelRoute, query, method string) []laravelRoute {
+	type scoredRoute struct {
+		route laravelRoute
+		score int
+	}
+
+	var matches []scoredRoute
+	for _, route := range routes {
+		if method != "" && !strings.Contains(strings.ToUpper(route.Method), strings.ToUpper(method)) {
+			continue
+		}
+
+		text := strings.Join([]string{route.Method, route.URI, route.Name, route.Action, strings.Join(route.middleware(), " ")}, " ")
+		total := 0
+		matched := true
+		for _, term := range strings.Fields(query) {
+			score, ok := fuzzyScore(term, text)
+			if !ok {
+				matched = false
+				break
+			}
+			total += score
+		}
+		if matched {
+			matches = append(matches, scoredRoute{route, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	filtered := make([]laravelRoute, len(matches))
+	for i, match := range matches {
+		filtered[i] = match.route
+	}
+	return filtered
+}
+
+// fuzzyScore reports whether all characters of term appear in text in order.
+// Substrings, consecutive characters and matches at word starts score higher.
+func fuzzyScore(term, text string) (int, bool) {
+	term = strings.ToLower(term)
+	lower := strings.ToLower(text)
+
+	if index := strings.Index(lower, term); index >= 0 {
+		score := 100 + len(term)*10
+		if index == 0 || !isWordRune(rune(lower[index-1])) {
+			score += 20
+		}
+		return score, true
+	}
+
+	score := 0
+	position := 0
+	previous := -2
+	for _, r := range term {
+		index := strings.IndexRune(lower[position:], r)
+		if index < 0 {
+			return 0, false
+		}
+		index += position
+
+		score++
+		if index == previous+1 {
+			score += 5
+		}
+		if index == 0 || !isWordRune(rune(lower[index-1])) {
+			score += 3
+		}
+		previous = index
+		position = index + len(string(r))
+	}
+	return score, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func printRoutes(routes []laravelRoute) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "METHOD\tURI\tNAME\tACTION\tMIDDLEWARE")
+	for _, route := range routes {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			route.Method,
+			route.URI,
+			route.Name,
+			strings.TrimPrefix(route.Action, `App\Http\Controllers\`),
+			strings.Join(route.middleware(), ", "),
+		)
+	}
+	writer.Flush()
+	fmt.Printf("%d route(s)\n", len(routes))
+}
+
+func openRouteAction(route laravelRoute) error {
+	class, method, _ := strings.Cut(route.Action, "@")
+	if class == "" || class == "Closure" {
+		return fmt.Errorf("%s %s is handled by a closure, look for it in routes/", route.Method, route.URI)
+	}
+
+	path, err := classFile(class)
+	if err != nil {
+		return err
+	}
+
+	if method == "" {
+		method = "__invoke"
+	}
+	line := methodLine(path, method)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	fmt.Printf("Opening %s:%d\n", path, line)
+	return openInEditor(cfg.Editor, path, line)
+}
+
+// classFile resolves a class to its file with the PSR-4 autoload rules of composer.json
+func classFile(class string) (string, error) {
+	data, err := os.ReadFile("composer.json")
+	if err != nil {
+		return "", fmt.Errorf("error reading composer.json: %v", err)
+	}
+
+	var composer struct {
+		Autoload struct {
+			PSR4 map[string]json.RawMessage `json:"psr-4"`
+		} `json:"autoload"`
+	}
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return "", fmt.Errorf("error parsing composer.json: %v", err)
+	}
+
+	class = strings.TrimPrefix(class, `\`)
+	for prefix, rawDirs := range composer.Autoload.PSR4 {
+		if !strings.HasPrefix(class, prefix) {
+			continue
+		}
+
+		var dirs []string
+		var dir string
+		if err := json.Unmarshal(rawDirs, &dir); err == nil {
+			dirs = []string{dir}
+		} else if err := json.Unmarshal(rawDirs, &dirs); err != nil {
+			continue
+		}
+
+		relative := strings.ReplaceAll(strings.TrimPrefix(class, prefix), `\`, "/") + ".php"
+		for _, dir := range dirs {
+			path := filepath.Join(dir, relative)
+			if fileExists(path) {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no file found for %s (only PSR-4 autoloaded classes are supported)", class)
+}
+
+// methodLine returns the line a method is declared on, or 1
+func methodLine(path, method string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 1
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "function "+method+"(") {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// openInEditor opens path at line with the editor's own syntax for line numbers
+func openInEditor(editor, path string, line int) error {
+	var cmd *exec.Cmd
+	switch filepath.Base(editor) {
+	case "vscode", "code", "cursor", "codium":
+		if editor == "vscode" {
+			editor = "code"
+		}
+		cmd = exec.Command(editor, "--goto", fmt.Sprintf("%s:%d", path, line))
+	case "vim", "nvim", "vi", "nano", "emacs", "hx", "micro":
+		cmd = exec.Command(editor, fmt.Sprintf("+%d", line), path)
+	case "subl", "zed":
+		cmd = exec.Command(editor, fmt.Sprintf("%s:%d", path, line))
+	case "phpstorm", "pstorm", "idea":
+		cmd = exec.Command(editor, "--line", fmt.Sprint(line), path)
+	default:
+		cmd = exec.Command(editor, path)
+	}
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleRouteList = `[
+	{"domain":null,"method":"GET|HEAD","uri":"\/","name":null,"action":"Closure","middleware":["web"]},
+	{"domain":null,"method":"GET|HEAD","uri":"orders","name":"orders.index","action":"App\\Http\\Controllers\\OrderController@index","middleware":["web","auth"]},
+	{"domain":null,"method":"POST","uri":"orders","name":"orders.store","action":"App\\Http\\Controllers\\OrderController@store","middleware":"web\nauth"},
+	{"domain":null,"method":"GET|HEAD","uri":"api\/users","name":"api.users.index","action":"App\\Http\\Controllers\\Api\\UserController@index","middleware":["api"]}
+]`
+
+func sampleRoutes(t *testing.T) []laravelRoute {
+	var routes []laravelRoute
+	if err := json.Unmarshal([]byte(sampleRouteList), &routes); err != nil {
+		t.Fatal(err)
+	}
+	return routes
+}
+
+func TestFilterRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		method string
+		want   []string
+	}{
+		{name: "all", want: []string{"/", "orders", "orders", "api/users"}},
+		{name: "method", method: "post", want: []string{"orders"}},
+		{name: "substring", query: "users", want: []string{"api/users"}},
+		{name: "middleware from string", query: "auth", method: "POST", want: []string{"orders"}},
+		{name: "several terms", query: "api users", want: []string{"api/users"}},
+		{name: "no match", query: "invoices", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, route := range filterRoutes(sampleRoutes(t), tt.query, tt.method) {
+				got = append(got, route.URI)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterRoutesOrder(t *testing.T) {
+	routes := filterRoutes(sampleRoutes(t), "ordstore", "")
+	if len(routes) == 0 || routes[0].Name != "orders.store" {
+		t.Errorf("best match for ordstore = %+v, want orders.store", routes)
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	substring, ok := fuzzyScore("user", "GET api/users UserController")
+	if !ok {
+		t.Fatal("substring should match")
+	}
+	scattered, ok := fuzzyScore("usr", "GET api/users UserController")
+	if !ok {
+		t.Fatal("subsequence should match")
+	}
+	if substring <= scattered {
+		t.Errorf("substring score %d should beat subsequence score %d", substring, scattered)
+	}
+	if _, ok := fuzzyScore("xyz", "GET api/users"); ok {
+		t.Error("missing characters should not match")
+	}
+}
+
+func TestClassFile(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	composer := `{"autoload": {"psr-4": {"App\\": "app/", "Modules\\": ["modules/", "src/"]}}}`
+	if err := os.WriteFile("composer.json", []byte(composer), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"app/Http/Controllers/OrderController.php", "src/Billing/InvoiceController.php"} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("<?php\n\nclass X\n{\n    public function index()\n    {\n    }\n}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, err := classFile(`App\Http\Controllers\OrderController`)
+	if err != nil || path != filepath.Join("app", "Http", "Controllers", "OrderController.php") {
+		t.Errorf("classFile() = %q, %v", path, err)
+	}
+	if line := methodLine(path, "index"); line != 5 {
+		t.Errorf("methodLine() = %d, want 5", line)
+	}
+
+	path, err = classFile(`Modules\Billing\InvoiceController`)
+	if err != nil || path != filepath.Join("src", "Billing", "InvoiceController.php") {
+		t.Errorf("classFile() with several dirs = %q, %v", path, err)
+	}
+
+	if _, err := classFile(`Vendor\Package\Controller`); err == nil {
+		t.Error("expected an error for a class outside the autoload rules")
+	}
+}
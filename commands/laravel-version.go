@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"mo/utils"
+)
+
+// laravelVersion is the installed laravel/framework version from composer.lock.
+// The zero value means the version is unknown.
+type laravelVersion struct {
+	Major   int
+	Minor   int
+	Version string
+}
+
+func (v laravelVersion) known() bool {
+	return v.Version != ""
+}
+
+// atLeast reports whether the version is known and major or newer, so
+// version dependent defaults fall back to what works everywhere
+func (v laravelVersion) atLeast(major int) bool {
+	return v.known() && v.Major >= major
+}
+
+// detectLaravelVersion reads the installed framework version from composer.lock
+func detectLaravelVersion(lockPath string) (laravelVersion, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return laravelVersion{}, err
+	}
+
+	var lock struct {
+		Packages []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return laravelVersion{}, fmt.Errorf("error parsing %s: %v", lockPath, err)
+	}
+
+	for _, pkg := range lock.Packages {
+		if pkg.Name == "laravel/framework" {
+			return parseLaravelVersion(pkg.Version)
+		}
+	}
+	return laravelVersion{}, fmt.Errorf("laravel/framework not found in %s", lockPath)
+}
+
+func parseLaravelVersion(version string) (laravelVersion, error) {
+	trimmed := strings.TrimPrefix(version, "v")
+	parts := strings.SplitN(trimmed, ".", 3)
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return laravelVersion{}, fmt.Errorf("unsupported laravel/framework version %q", version)
+	}
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	return laravelVersion{Major: major, Minor: minor, Version: trimmed}, nil
+}
+
+// projectLaravelVersion returns the version of the project in the current
+// directory, or the zero value if it can't be detected
+func projectLaravelVersion() laravelVersion {
+	version, _ := detectLaravelVersion("composer.lock")
+	return version
+}
+
+// defaultArtisanCommands returns the l:clear or l:cache commands for the version.
+// optimize:clear exists since Laravel 5.7 but what it clears changed between
+// releases, so it only replaces the explicit list from Laravel 8 on, where it
+// clears at least the same caches. Without a version the explicit list runs
+// since it works everywhere. optimize caches config, events, routes and views
+// since Laravel 11.
+func defaultArtisanCommands(kind string, version laravelVersion) []string {
+	if kind == "cache" {
+		if version.atLeast(11) {
+			return []string{"optimize"}
+		}
+		return defaultCacheCommands
+	}
+
+	if version.atLeast(8) {
+		return []string{"optimize:clear"}
+	}
+	return defaultClearCommands
+}
+
+// cacheStoreKey returns the env key of the cache store, which Laravel 11
+// renamed from CACHE_DRIVER to CACHE_STORE. Without a version the key already
+// used in the env file wins.
+func cacheStoreKey(version laravelVersion, envManager *utils.EnvManager) string {
+	if version.known() {
+		if version.atLeast(11) {
+			return "CACHE_STORE"
+		}
+		return "CACHE_DRIVER"
+	}
+
+	if _, found, _ := envManager.GetVar("CACHE_DRIVER"); found {
+		if _, found, _ := envManager.GetVar("CACHE_STORE"); !found {
+			return "CACHE_DRIVER"
+		}
+	}
+	return "CACHE_STORE"
+}
+
+// checkLaravelVersion reports the detected version for l:doctor and flags the
+// renamed cache key
+func checkLaravelVersion(version laravelVersion, envManager *utils.EnvManager) doctorCheck {
+	check := doctorCheck{Name: "Laravel", Fix: "run \"composer install\""}
+
+	if !version.known() {
+		return doctorWarn.with(check, "version unknown, composer.lock is missing or has no laravel/framework")
+	}
+
+	if version.atLeast(11) {
+		_, hasDriver, _ := envManager.GetVar("CACHE_DRIVER")
+		_, hasStore, _ := envManager.GetVar("CACHE_STORE")
+		if hasDriver && !hasStore {
+			check.Fix = "rename CACHE_DRIVER to CACHE_STORE in .env"
+			return doctorWarn.with(check, fmt.Sprintf("%s ignores CACHE_DRIVER", version.Version))
+		}
+	}
+	return doctorPass.with(check, version.Version)
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mo/utils"
+)
+
+func TestDetectLaravelVersion(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "composer.lock")
+	lock := `{"packages": [{"name": "brick/math", "version": "0.12.1"}, {"name": "laravel/framework", "version": "v11.9.2"}]}`
+	if err := os.WriteFile(lockPath, []byte(lock), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := detectLaravelVersion(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Major != 11 || version.Minor != 9 || version.Version != "11.9.2" {
+		t.Errorf("version = %+v", version)
+	}
+
+	if _, err := detectLaravelVersion(filepath.Join(dir, "missing.lock")); err == nil {
+		t.Error("expected an error for a missing lock file")
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		version laravelVersion
+		major   int
+		want    bool
+	}{
+		{version: laravelVersion{Major: 10, Version: "10.48.0"}, major: 8, want: true},
+		{version: laravelVersion{Major: 8, Version: "8.0.0"}, major: 8, want: true},
+		{version: laravelVersion{Major: 7, Version: "7.30.6"}, major: 8, want: false},
+		{version: laravelVersion{}, major: 8, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.version.atLeast(tt.major); got != tt.want {
+			t.Errorf("%q.atLeast(%d) = %v, want %v", tt.version.Version, tt.major, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultArtisanCommands(t *testing.T) {
+	tests := []struct {
+		version string
+		kind    string
+		want    []string
+	}{
+		{version: "", kind: "clear", want: defaultClearCommands},
+		{version: "5.8.38", kind: "clear", want: defaultClearCommands},
+		{version: "7.30.6", kind: "clear", want: defaultClearCommands},
+		{version: "8.0.0", kind: "clear", want: []string{"optimize:clear"}},
+		{version: "8.83.27", kind: "clear", want: []string{"optimize:clear"}},
+		{version: "11.0.0", kind: "clear", want: []string{"optimize:clear"}},
+		{version: "", kind: "cache", want: defaultCacheCommands},
+		{version: "7.30.6", kind: "cache", want: defaultCacheCommands},
+		{version: "10.48.0", kind: "cache", want: defaultCacheCommands},
+		{version: "11.0.0", kind: "cache", want: []string{"optimize"}},
+		{version: "12.1.0", kind: "cache", want: []string{"optimize"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+" "+tt.version, func(t *testing.T) {
+			var version laravelVersion
+			if tt.version != "" {
+				parsed, err := parseLaravelVersion(tt.version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				version = parsed
+			}
+
+			got := defaultArtisanCommands(tt.kind, version)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheStoreKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		env     string
+		want    string
+	}{
+		{name: "laravel 10", version: "10.48.0", env: "CACHE_STORE=file\n", want: "CACHE_DRIVER"},
+		{name: "laravel 11", version: "11.0.0", env: "CACHE_DRIVER=file\n", want: "CACHE_STORE"},
+		{name: "unknown with old key", env: "CACHE_DRIVER=file\n", want: "CACHE_DRIVER"},
+		{name: "unknown without key", env: "APP_ENV=local\n", want: "CACHE_STORE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envPath := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(envPath, []byte(tt.env), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var version laravelVersion
+			if tt.version != "" {
+				version, _ = parseLaravelVersion(tt.version)
+			}
+
+			if got := cacheStoreKey(version, utils.NewEnvManager(envPath)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
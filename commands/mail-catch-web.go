@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// caughtMessageSummary is the list view of a message returned by the JSON API
+type caughtMessageSummary struct {
+	ID          string    `json:"id"`
+	From        string    `json:"from"`
+	To          []string  `json:"to"`
+	Subject     string    `json:"subject"`
+	Date        time.Time `json:"date"`
+	Size        int       `json:"size"`
+	Attachments int       `json:"attachments"`
+}
+
+func newMailWebHandler(store *mailStore) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, mailInboxHTML)
+	})
+
+	mux.HandleFunc("/api/messages", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			summaries := []caughtMessageSummary{}
+			for _, msg := range store.list() {
+				summaries = append(summaries, caughtMessageSummary{
+					ID:          msg.ID,
+					From:        msg.From,
+					To:          msg.To,
+					Subject:     msg.Subject,
+					Date:        msg.Date,
+					Size:        msg.Size,
+					Attachments: len(msg.Attachments),
+				})
+			}
+			writeJSON(w, summaries)
+		case http.MethodDelete:
+			store.clear()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/messages/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/messages/"), "/"), "/")
+		msg := store.get(parts[0])
+		if msg == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			store.delete(msg.ID)
+			w.WriteHeader(http.StatusNoContent)
+		case len(parts) == 1:
+			writeJSON(w, msg)
+		case len(parts) == 2 && parts[1] == "html":
+			// Rendered inside a sandboxed iframe, scripts in the email never run
+			w.Header().Set("Content-Security-Policy", "sandbox")
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, msg.HTML)
+		case len(parts) == 2 && parts[1] == "source":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write(msg.raw)
+		case len(parts) == 2 && parts[1] == "eml":
+			w.Header().Set("Content-Type", "message/rfc822")
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": msg.ID + ".eml"}))
+			w.Write(msg.raw)
+		case len(parts) == 3 && parts[1] == "attachments":
+			index, err := strconv.Atoi(parts[2])
+			if err != nil || index < 0 || index >= len(msg.Attachments) {
+				http.NotFound(w, r)
+				return
+			}
+			attachment := msg.Attachments[index]
+			w.Header().Set("Content-Type", attachment.ContentType)
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+			w.Write(attachment.data)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+const mailInboxHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>mo mail catcher</title>
+<style>
+  body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, sans-serif; font-size: 14px; display: flex; height: 100vh; color: #222; }
+  #list { width: 360px; border-right: 1px solid #ddd; overflow-y: auto; }
+  #list header { display: flex; justify-content: space-between; align-items: center; padding: 10px; border-bottom: 1px solid #ddd; background: #f7f7f7; }
+  .item { padding: 10px; border-bottom: 1px solid #eee; cursor: pointer; }
+  .item:hover, .item.active { background: #eef4ff; }
+  .item .subject { font-weight: 600; }
+  .item .meta { color: #777; font-size: 12px; }
+  #detail { flex: 1; display: flex; flex-direction: column; }
+  #detail header { padding: 10px 16px; border-bottom: 1px solid #ddd; }
+  #detail header div { margin: 2px 0; }
+  #tabs button { margin-right: 4px; }
+  #tabs button.active { font-weight: 700; }
+  #body { flex: 1; overflow: auto; }
+  #body iframe { border: 0; width: 100%; height: 100%; }
+  #body pre { margin: 0; padding: 16px; white-space: pre-wrap; }
+  .empty { padding: 20px; color: #999; }
+</style>
+</head>
+<body>
+<div id="list">
+  <header><strong>Inbox</strong><button onclick="clearAll()">Clear</button></header>
+  <div id="items"></div>
+</div>
+<div id="detail"><div class="empty">Select a message</div></div>
+<script>
+let current = null;
+let view = "html";
+
+function esc(s) {
+  return String(s || "").replace(/[&<>"]/g, c => ({"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"}[c]));
+}
+
+async function load() {
+  const messages = await (await fetch("/api/messages")).json();
+  const items = document.getElementById("items");
+  if (messages.length === 0) {
+    items.innerHTML = '<div class="empty">No messages yet</div>';
+    return;
+  }
+  items.innerHTML = messages.map(m =>
+    '<div class="item' + (current && current.id === m.id ? ' active' : '') + '" onclick="show(\'' + m.id + '\')">' +
+    '<div class="subject">' + esc(m.subject || "(no subject)") + '</div>' +
+    '<div class="meta">' + esc(m.from) + ' &rarr; ' + esc((m.to || []).join(", ")) + '</div>' +
+    '<div class="meta">' + new Date(m.date).toLocaleString() + (m.attachments ? ' &middot; ' + m.attachments + ' attachment(s)' : '') + '</div>' +
+    '</div>').join("");
+}
+
+async function show(id) {
+  current = await (await fetch("/api/messages/" + id)).json();
+  view = current.html ? "html" : "text";
+  render();
+  load();
+}
+
+function render() {
+  const m = current;
+  const attachments = m.attachments.map((a, i) =>
+    '<a href="/api/messages/' + m.id + '/attachments/' + i + '">' + esc(a.filename) + '</a> (' + a.size + ' bytes)').join(", ");
+  let body = "";
+  if (view === "html") {
+    body = '<iframe sandbox src="/api/messages/' + m.id + '/html"></iframe>';
+  } else if (view === "text") {
+    body = '<pre>' + esc(m.text) + '</pre>';
+  } else {
+    body = '<iframe src="/api/messages/' + m.id + '/source"></iframe>';
+  }
+  document.getElementById("detail").innerHTML =
+    '<header>' +
+    '<div><strong>' + esc(m.subject || "(no subject)") + '</strong></div>' +
+    '<div>From: ' + esc(m.from) + '</div>' +
+    '<div>To: ' + esc((m.to || []).join(", ")) + '</div>' +
+    '<div>Date: ' + new Date(m.date).toLocaleString() + '</div>' +
+    (attachments ? '<div>Attachments: ' + attachments + '</div>' : '') +
+    '<div id="tabs">' +
+    ['html', 'text', 'source'].map(t => '<button class="' + (t === view ? 'active' : '') + '" onclick="view=\'' + t + '\';render()">' + t.toUpperCase() + '</button>').join("") +
+    '<a href="/api/messages/' + m.id + '/eml">Download .eml</a> ' +
+    '<button onclick="remove(\'' + m.id + '\')">Delete</button>' +
+    '</div></header>' +
+    '<div id="body">' + body + '</div>';
+}
+
+async function remove(id) {
+  await fetch("/api/messages/" + id, {method: "DELETE"});
+  current = null;
+  document.getElementById("detail").innerHTML = '<div class="empty">Select a message</div>';
+  load();
+}
+
+async function clearAll() {
+  await fetch("/api/messages", {method: "DELETE"});
+  current = null;
+  document.getElementById("detail").innerHTML = '<div class="empty">Select a message</div>';
+  load();
+}
+
+load();
+setInterval(load, 3000);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,497 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// caughtMessage is a parsed email received by mail:catch
+type caughtMessage struct {
+	ID          string             `json:"id"`
+	From        string             `json:"from"`
+	To          []string           `json:"to"`
+	Subject     string             `json:"subject"`
+	Date        time.Time          `json:"date"`
+	Text        string             `json:"text,omitempty"`
+	HTML        string             `json:"html,omitempty"`
+	Headers     map[string]string  `json:"headers,omitempty"`
+	Attachments []caughtAttachment `json:"attachments"`
+	Size        int                `json:"size"`
+	raw         []byte
+}
+
+type caughtAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	data        []byte
+}
+
+// mailStore keeps caught messages in memory and optionally mirrors them to disk as .eml files
+type mailStore struct {
+	mu       sync.RWMutex
+	messages []*caughtMessage
+	dir      string
+	counter  int
+}
+
+func newMailStore(dir string) (*mailStore, error) {
+	store := &mailStore{dir: dir}
+	if dir == "" {
+		return store, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file, err)
+		}
+		msg, err := parseCaughtMessage(raw)
+		if err != nil {
+			log.Printf("Skipping %s: %v", file, err)
+			continue
+		}
+		msg.ID = strings.TrimSuffix(filepath.Base(file), ".eml")
+		store.messages = append(store.messages, msg)
+	}
+
+	return store, nil
+}
+
+func (s *mailStore) add(msg *caughtMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counter++
+	msg.ID = fmt.Sprintf("%d-%d", time.Now().UnixNano(), s.counter)
+
+	if s.dir != "" {
+		if err := os.WriteFile(filepath.Join(s.dir, msg.ID+".eml"), msg.raw, 0644); err != nil {
+			return fmt.Errorf("error storing message: %w", err)
+		}
+	}
+
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *mailStore) list() []*caughtMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*caughtMessage, len(s.messages))
+	for i, msg := range s.messages {
+		list[len(s.messages)-1-i] = msg
+	}
+	return list
+}
+
+func (s *mailStore) get(id string) *caughtMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, msg := range s.messages {
+		if msg.ID == id {
+			return msg
+		}
+	}
+	return nil
+}
+
+func (s *mailStore) delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, msg := range s.messages {
+		if msg.ID == id {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			if s.dir != "" {
+				os.Remove(filepath.Join(s.dir, id+".eml"))
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (s *mailStore) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir != "" {
+		for _, msg := range s.messages {
+			os.Remove(filepath.Join(s.dir, msg.ID+".eml"))
+		}
+	}
+	s.messages = nil
+}
+
+func MailCatch(cliContext *cli.Context) error {
+	ip := cliContext.String("ip")
+	smtpAddress := net.JoinHostPort(ip, cliContext.String("smtp-port"))
+	webAddress := net.JoinHostPort(ip, cliContext.String("web-port"))
+
+	store, err := newMailStore(cliContext.String("dir"))
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", smtpAddress)
+	if err != nil {
+		return fmt.Errorf("error starting SMTP server on %s: %w", smtpAddress, err)
+	}
+	defer listener.Close()
+
+	webServer := &http.Server{Addr: webAddress, Handler: newMailWebHandler(store)}
+	webErrors := make(chan error, 1)
+	go func() {
+		if err := webServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			webErrors <- err
+		}
+	}()
+	defer webServer.Close()
+
+	go serveSMTP(listener, store)
+
+	fmt.Printf("SMTP server listening on %s\n", smtpAddress)
+	fmt.Printf("Web inbox available at http://%s\n", webAddress)
+	if store.dir != "" {
+		fmt.Printf("Storing messages in %s (%d loaded)\n", store.dir, len(store.messages))
+	}
+	fmt.Println("Press Ctrl-C to stop.")
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-webErrors:
+		return fmt.Errorf("error starting web server on %s: %w", webAddress, err)
+	case <-signals:
+		fmt.Println("\nStopping mail catcher...")
+		return nil
+	}
+}
+
+func serveSMTP(listener net.Listener, store *mailStore) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go handleSMTPConnection(conn, store)
+	}
+}
+
+// handleSMTPConnection implements just enough of RFC 5321 for apps to deliver mail.
+// Any credentials are accepted so projects with MAIL_USERNAME set keep working.
+func handleSMTPConnection(conn net.Conn, store *mailStore) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	reply := func(format string, args ...interface{}) {
+		text.PrintfLine(format, args...)
+	}
+
+	var from string
+	var hasSender bool
+	var recipients []string
+	reset := func() {
+		from = ""
+		hasSender = false
+		recipients = nil
+	}
+
+	reply("220 mo mail catcher ESMTP")
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Minute))
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			reset()
+			text.PrintfLine("250-mo mail catcher")
+			text.PrintfLine("250-8BITMIME")
+			text.PrintfLine("250-SIZE 52428800")
+			reply("250 AUTH PLAIN LOGIN")
+		case "HELO":
+			reset()
+			reply("250 mo mail catcher")
+		case "AUTH":
+			fields := strings.Fields(arg)
+			if len(fields) > 0 && strings.EqualFold(fields[0], "LOGIN") {
+				reply("334 VXNlcm5hbWU6")
+				if _, err := text.ReadLine(); err != nil {
+					return
+				}
+				reply("334 UGFzc3dvcmQ6")
+				if _, err := text.ReadLine(); err != nil {
+					return
+				}
+			} else if len(fields) == 1 {
+				reply("334 ")
+				if _, err := text.ReadLine(); err != nil {
+					return
+				}
+			}
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			from = extractSMTPAddress(arg)
+			hasSender = true
+			recipients = nil
+			reply("250 2.1.0 OK")
+		case "RCPT":
+			if !hasSender {
+				reply("503 5.5.1 MAIL FROM required first")
+				continue
+			}
+			recipients = append(recipients, extractSMTPAddress(arg))
+			reply("250 2.1.5 OK")
+		case "DATA":
+			if len(recipients) == 0 {
+				reply("503 5.5.1 RCPT TO required first")
+				continue
+			}
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			raw, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+
+			msg, err := parseCaughtMessage(raw)
+			if err != nil {
+				reply("554 5.6.0 Could not parse message: %v", err)
+				reset()
+				continue
+			}
+			if msg.From == "" {
+				msg.From = from
+			}
+			if len(msg.To) == 0 {
+				msg.To = recipients
+			}
+
+			if err := store.add(msg); err != nil {
+				reply("451 4.3.0 %v", err)
+			} else {
+				log.Printf("Caught message %q from %s to %s", msg.Subject, msg.From, strings.Join(msg.To, ", "))
+				reply("250 2.0.0 OK queued as %s", msg.ID)
+			}
+			reset()
+		case "RSET":
+			reset()
+			reply("250 2.0.0 OK")
+		case "NOOP":
+			reply("250 2.0.0 OK")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("502 5.5.2 Command not implemented")
+		}
+	}
+}
+
+func extractSMTPAddress(arg string) string {
+	if start := strings.IndexByte(arg, '<'); start >= 0 {
+		if end := strings.IndexByte(arg[start:], '>'); end >= 0 {
+			return arg[start+1 : start+end]
+		}
+	}
+	if i := strings.IndexByte(arg, ':'); i >= 0 {
+		return strings.TrimSpace(arg[i+1:])
+	}
+	return arg
+}
+
+func parseCaughtMessage(raw []byte) (*caughtMessage, error) {
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := new(mime.WordDecoder)
+	decodeHeader := func(value string) string {
+		decoded, err := decoder.DecodeHeader(value)
+		if err != nil {
+			return value
+		}
+		return decoded
+	}
+
+	msg := &caughtMessage{
+		From:        decodeHeader(parsed.Header.Get("From")),
+		Subject:     decodeHeader(parsed.Header.Get("Subject")),
+		Headers:     map[string]string{},
+		Attachments: []caughtAttachment{},
+		Size:        len(raw),
+		raw:         raw,
+	}
+
+	for key, values := range parsed.Header {
+		msg.Headers[key] = decodeHeader(strings.Join(values, ", "))
+	}
+
+	for _, header := range []string{"To", "Cc"} {
+		if list, err := parsed.Header.AddressList(header); err == nil {
+			for _, address := range list {
+				if address.Name != "" {
+					msg.To = append(msg.To, fmt.Sprintf("%s <%s>", address.Name, address.Address))
+				} else {
+					msg.To = append(msg.To, address.Address)
+				}
+			}
+		}
+	}
+
+	if date, err := parsed.Header.Date(); err == nil {
+		msg.Date = date
+	} else {
+		msg.Date = time.Now()
+	}
+
+	err = parseMessagePart(msg, textproto.MIMEHeader(parsed.Header), parsed.Body)
+	return msg, err
+}
+
+func parseMessagePart(msg *caughtMessage, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+		params = map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := parseMessagePart(msg, part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+
+	if disposition == "attachment" || filename != "" {
+		if filename == "" {
+			filename = "attachment"
+		}
+		msg.Attachments = append(msg.Attachments, caughtAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Size:        len(data),
+			data:        data,
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/html":
+		msg.HTML += string(data)
+	case "text/plain":
+		msg.Text += string(data)
+	default:
+		msg.Attachments = append(msg.Attachments, caughtAttachment{
+			Filename:    "part" + extensionForMediaType(mediaType),
+			ContentType: mediaType,
+			Size:        len(data),
+			data:        data,
+		})
+	}
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineStripper{reader: bufio.NewReader(body)})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper removes line breaks so base64 bodies can be decoded as one stream
+type newlineStripper struct {
+	reader *bufio.Reader
+}
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	count := 0
+	for count < len(p) {
+		b, err := n.reader.ReadByte()
+		if err != nil {
+			return count, err
+		}
+		if b == '\r' || b == '\n' || b == ' ' || b == '\t' {
+			continue
+		}
+		p[count] = b
+		count++
+	}
+	return count, nil
+}
+
+func extensionForMediaType(mediaType string) string {
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ".bin"
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestParseCaughtMessage_Multipart(t *testing.T) {
+	raw := "From: App <app@example.test>\r\n" +
+		"To: Bob <bob@example.test>, alice@example.test\r\n" +
+		"Subject: =?UTF-8?B?R3LDvMOfZQ==?=\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+		"\r\n" +
+		"--outer\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+		"\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Hello =3D world\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		"<p>Hello</p>\r\n" +
+		"--inner--\r\n" +
+		"--outer\r\n" +
+		"Content-Type: application/pdf; name=\"invoice.pdf\"\r\n" +
+		"Content-Disposition: attachment; filename=\"invoice.pdf\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"JVBERi0x\r\n" +
+		"LjQK\r\n" +
+		"--outer--\r\n"
+
+	msg, err := parseCaughtMessage([]byte(raw))
+	if err != nil {
+		t.Fatalf("parseCaughtMessage() error = %v", err)
+	}
+
+	if msg.Subject != "Grüße" {
+		t.Errorf("Subject = %q, want %q", msg.Subject, "Grüße")
+	}
+	if len(msg.To) != 2 || msg.To[0] != "Bob <bob@example.test>" || msg.To[1] != "alice@example.test" {
+		t.Errorf("To = %v", msg.To)
+	}
+	if msg.Text != "Hello = world" {
+		t.Errorf("Text = %q, want %q", msg.Text, "Hello = world")
+	}
+	if msg.HTML != "<p>Hello</p>" {
+		t.Errorf("HTML = %q, want %q", msg.HTML, "<p>Hello</p>")
+	}
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("Attachments = %d, want 1", len(msg.Attachments))
+	}
+	if msg.Attachments[0].Filename != "invoice.pdf" || string(msg.Attachments[0].data) != "%PDF-1.4\n" {
+		t.Errorf("Attachment = %q %q", msg.Attachments[0].Filename, msg.Attachments[0].data)
+	}
+}
+
+func TestExtractSMTPAddress(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"FROM:<app@example.test>", "app@example.test"},
+		{"TO:<bob@example.test> NOTIFY=NEVER", "bob@example.test"},
+		{"FROM:<>", ""},
+		{"TO: bob@example.test", "bob@example.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if got := extractSMTPAddress(tt.arg); got != tt.want {
+				t.Errorf("extractSMTPAddress(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"mo/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// mailSettings holds the MAIL_* values read from .env
+type mailSettings struct {
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	Encryption string
+	From       string
+}
+
+func MailTest(cliContext *cli.Context) error {
+	to := cliContext.Args().First()
+	if to == "" {
+		return fmt.Errorf("usage: mail:test <to>")
+	}
+
+	settings, err := readMailSettings(utils.NewEnvManager(".env"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sending test email to %s via %s:%s", to, settings.Host, settings.Port)
+	if settings.Encryption != "" {
+		fmt.Printf(" (%s)", settings.Encryption)
+	}
+	fmt.Println("...")
+
+	transcript := &bytes.Buffer{}
+	err = sendTestMail(settings, to, transcript, cliContext.Duration("timeout"))
+	if err != nil || cliContext.Bool("verbose") {
+		fmt.Println("SMTP conversation:")
+		fmt.Println("----------------------------")
+		fmt.Print(transcript.String())
+		fmt.Println("----------------------------")
+	}
+	if err != nil {
+		return fmt.Errorf("sending test email failed: %w", err)
+	}
+
+	fmt.Println("✓ Test email sent successfully!")
+	return nil
+}
+
+func readMailSettings(envManager *utils.EnvManager) (*mailSettings, error) {
+	if !fileExists(envManager.Path) {
+		return nil, fmt.Errorf("%s file not found", envManager.Path)
+	}
+
+	values := map[string]string{}
+	for _, key := range []string{"MAIL_HOST", "MAIL_PORT", "MAIL_USERNAME", "MAIL_PASSWORD", "MAIL_ENCRYPTION", "MAIL_FROM_ADDRESS"} {
+		value, _, err := envManager.GetVar(key)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", key, err)
+		}
+		values[key] = utils.UnquoteValue(value)
+	}
+
+	settings := &mailSettings{
+		Host:       values["MAIL_HOST"],
+		Port:       values["MAIL_PORT"],
+		Username:   values["MAIL_USERNAME"],
+		Password:   values["MAIL_PASSWORD"],
+		Encryption: strings.ToLower(values["MAIL_ENCRYPTION"]),
+		From:       values["MAIL_FROM_ADDRESS"],
+	}
+
+	if settings.Host == "" {
+		return nil, fmt.Errorf("MAIL_HOST is not set in %s", envManager.Path)
+	}
+	if settings.Port == "" {
+		settings.Port = "25"
+	}
+	if settings.From == "" {
+		settings.From = "mo@localhost"
+	}
+
+	return settings, nil
+}
+
+// transcriptConn records everything read from and written to the connection
+type transcriptConn struct {
+	net.Conn
+	log   io.Writer
+	muted bool
+}
+
+func (c *transcriptConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	writeTranscript(c.log, "S: ", p[:n])
+	return n, err
+}
+
+func (c *transcriptConn) Write(p []byte) (int, error) {
+	if !c.muted {
+		writeTranscript(c.log, "C: ", p)
+	}
+	return c.Conn.Write(p)
+}
+
+func writeTranscript(w io.Writer, prefix string, data []byte) {
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s%s", prefix, strings.TrimRight(line, "\r\n"))
+		fmt.Fprintln(w)
+	}
+}
+
+// smtpClient is a minimal SMTP client that keeps the conversation readable for debugging
+type smtpClient struct {
+	conn       net.Conn
+	recorder   *transcriptConn
+	text       *textproto.Conn
+	transcript io.Writer
+	extensions map[string]string
+	tls        bool
+}
+
+func (c *smtpClient) attach(conn net.Conn) {
+	c.conn = conn
+	c.recorder = &transcriptConn{Conn: conn, log: c.transcript}
+	c.text = textproto.NewConn(c.recorder)
+}
+
+func (c *smtpClient) cmd(expectCode int, format string, args ...interface{}) (string, error) {
+	id, err := c.text.Cmd(format, args...)
+	if err != nil {
+		return "", err
+	}
+	c.text.StartResponse(id)
+	defer c.text.EndResponse(id)
+	_, msg, err := c.text.ReadResponse(expectCode)
+	return msg, err
+}
+
+func (c *smtpClient) hello() error {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+
+	msg, err := c.cmd(250, "EHLO %s", hostname)
+	if err != nil {
+		if _, heloErr := c.cmd(250, "HELO %s", hostname); heloErr != nil {
+			return fmt.Errorf("EHLO/HELO rejected: %w", err)
+		}
+		c.extensions = map[string]string{}
+		return nil
+	}
+
+	c.extensions = map[string]string{}
+	lines := strings.Split(msg, "\n")
+	for _, line := range lines[1:] {
+		parts := strings.SplitN(line, " ", 2)
+		args := ""
+		if len(parts) > 1 {
+			args = parts[1]
+		}
+		c.extensions[strings.ToUpper(parts[0])] = args
+	}
+	return nil
+}
+
+func (c *smtpClient) startTLS(serverName string) error {
+	if _, ok := c.extensions["STARTTLS"]; !ok {
+		return fmt.Errorf("server does not support STARTTLS")
+	}
+	if _, err := c.cmd(220, "STARTTLS"); err != nil {
+		return err
+	}
+
+	tlsConn := tls.Client(c.conn, &tls.Config{ServerName: serverName})
+	if err := tlsConn.Handshake(); err != nil {
+		return fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	fmt.Fprintln(c.transcript, "-- TLS connection established --")
+
+	c.attach(tlsConn)
+	c.tls = true
+	return c.hello()
+}
+
+func (c *smtpClient) auth(username, password string) error {
+	mechanisms := strings.Fields(strings.ToUpper(c.extensions["AUTH"]))
+	if len(mechanisms) == 0 {
+		return fmt.Errorf("server does not support AUTH")
+	}
+
+	if contains(mechanisms, "PLAIN") {
+		credentials := base64.StdEncoding.EncodeToString([]byte("\x00" + username + "\x00" + password))
+		fmt.Fprintln(c.transcript, "C: AUTH PLAIN ****")
+		_, err := c.quietCmd(235, "AUTH PLAIN %s", credentials)
+		return err
+	}
+
+	if contains(mechanisms, "LOGIN") {
+		if _, err := c.cmd(334, "AUTH LOGIN"); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.transcript, "C: ****")
+		if _, err := c.quietCmd(334, "%s", base64.StdEncoding.EncodeToString([]byte(username))); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.transcript, "C: ****")
+		_, err := c.quietCmd(235, "%s", base64.StdEncoding.EncodeToString([]byte(password)))
+		return err
+	}
+
+	return fmt.Errorf("no supported AUTH mechanism (server offers: %s)", strings.Join(mechanisms, ", "))
+}
+
+// quietCmd sends a command without recording it, so credentials never end up in the transcript
+func (c *smtpClient) quietCmd(expectCode int, format string, args ...interface{}) (string, error) {
+	c.recorder.muted = true
+	defer func() { c.recorder.muted = false }()
+	return c.cmd(expectCode, format, args...)
+}
+
+func sendTestMail(settings *mailSettings, to string, transcript io.Writer, timeout time.Duration) error {
+	address := net.JoinHostPort(settings.Host, settings.Port)
+	implicitTLS := settings.Encryption == "ssl" || settings.Encryption == "smtps" || settings.Port == "465"
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: settings.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		fmt.Fprintf(transcript, "-- could not connect to %s: %v --\n", address, err)
+		return err
+	}
+	defer conn.Close()
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	fmt.Fprintf(transcript, "-- connected to %s --\n", address)
+	client := &smtpClient{transcript: transcript, tls: implicitTLS}
+	client.attach(conn)
+	return client.sendMail(settings, to)
+}
+
+// sendMail runs the SMTP conversation on the attached connection, from the
+// greeting to QUIT
+func (c *smtpClient) sendMail(settings *mailSettings, to string) error {
+	if _, _, err := c.text.ReadResponse(220); err != nil {
+		return fmt.Errorf("unexpected greeting: %w", err)
+	}
+	if err := c.hello(); err != nil {
+		return err
+	}
+
+	if !c.tls && settings.Encryption == "tls" {
+		if err := c.startTLS(settings.Host); err != nil {
+			return err
+		}
+	}
+
+	if settings.Username != "" {
+		if err := c.auth(settings.Username, settings.Password); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if _, err := c.cmd(250, "MAIL FROM:<%s>", settings.From); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	if _, err := c.cmd(25, "RCPT TO:<%s>", to); err != nil {
+		return fmt.Errorf("RCPT TO rejected: %w", err)
+	}
+	if _, err := c.cmd(354, "DATA"); err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+
+	writer := c.text.DotWriter()
+	if _, err := writer.Write(buildTestMessage(settings, to)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if _, _, err := c.text.ReadResponse(250); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	c.cmd(221, "QUIT")
+	return nil
+}
+
+func buildTestMessage(settings *mailSettings, to string) []byte {
+	hostname, _ := os.Hostname()
+	now := time.Now()
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", settings.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: Test email from mo\r\n")
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%d.mo@%s>\r\n", now.UnixNano(), hostname)
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n")
+	fmt.Fprintf(&msg, "\r\n")
+	fmt.Fprintf(&msg, "This is a test email sent by mo.\r\n\r\n")
+	fmt.Fprintf(&msg, "Host: %s\r\n", settings.Host)
+	fmt.Fprintf(&msg, "Port: %s\r\n", settings.Port)
+	fmt.Fprintf(&msg, "Encryption: %s\r\n", valueOrNone(settings.Encryption))
+	fmt.Fprintf(&msg, "Sent at: %s\r\n", now.Format(time.RFC3339))
+	return msg.Bytes()
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer answers the client's commands on conn until QUIT. rcptReply is
+// the reply line to RCPT TO.
+func fakeSMTPServer(conn net.Conn, rcptReply string) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	text.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			text.PrintfLine("250-fake\r\n250-AUTH PLAIN LOGIN\r\n250 8BITMIME")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			text.PrintfLine("235 2.7.0 Authentication successful")
+		case strings.HasPrefix(command, "MAIL FROM"):
+			text.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO"):
+			text.PrintfLine("%s", rcptReply)
+		case command == "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			if _, err := text.ReadDotBytes(); err != nil {
+				return
+			}
+			text.PrintfLine("250 OK queued")
+		case command == "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPClientSendMail(t *testing.T) {
+	settings := &mailSettings{Host: "smtp.example.com", Port: "587", Username: "shop", Password: "hunter2", From: "shop@example.com"}
+
+	tests := []struct {
+		name      string
+		rcptReply string
+		wantErr   string
+	}{
+		{name: "accepted", rcptReply: "250 OK"},
+		{name: "forwarded", rcptReply: "251 User not local; will forward"},
+		{name: "rejected", rcptReply: "550 5.1.1 No such user", wantErr: "RCPT TO rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConn, serverConn := net.Pipe()
+			defer clientConn.Close()
+			defer serverConn.Close()
+			go fakeSMTPServer(serverConn, tt.rcptReply)
+
+			var transcript bytes.Buffer
+			client := &smtpClient{transcript: &transcript}
+			client.attach(clientConn)
+
+			err := client.sendMail(settings, "dev@example.com")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sendMail() error = %v\n%s", err, transcript.String())
+			}
+
+			output := transcript.String()
+			if !strings.Contains(output, "C: AUTH PLAIN ****\n") {
+				t.Errorf("transcript is missing the masked AUTH line:\n%s", output)
+			}
+			credentials := base64.StdEncoding.EncodeToString([]byte("\x00shop\x00hunter2"))
+			if strings.Contains(output, credentials) || strings.Contains(output, "hunter2") {
+				t.Errorf("transcript leaks the credentials:\n%s", output)
+			}
+			if !strings.Contains(output, "S: 221 Bye") {
+				t.Errorf("transcript is missing QUIT:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// nodePackageManager is the package manager of a Node.js project and whether
+// its lockfile exists
+type nodePackageManager struct {
+	Name        string
+	HasLockfile bool
+	// YarnBerry is set for Yarn 2 and newer, which replaced --frozen-lockfile with --immutable
+	YarnBerry bool
+}
+
+// packageManagerLockfiles are checked in order, the first match wins
+var packageManagerLockfiles = []struct {
+	name  string
+	files []string
+}{
+	{name: "pnpm", files: []string{"pnpm-lock.yaml"}},
+	{name: "yarn", files: []string{"yarn.lock"}},
+	{name: "bun", files: []string{"bun.lockb", "bun.lock"}},
+	{name: "npm", files: []string{"package-lock.json", "npm-shrinkwrap.json"}},
+}
+
+// detectPackageManager reads the packageManager field of package.json
+// ("pnpm@9.1.0") and falls back to the lockfile in dir, then to npm
+func detectPackageManager(dir string) nodePackageManager {
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		json.Unmarshal(data, &pkg)
+	}
+
+	name, version, _ := strings.Cut(pkg.PackageManager, "@")
+	if !isKnownPackageManager(name) {
+		name, version = "", ""
+		for _, candidate := range packageManagerLockfiles {
+			if hasAnyFile(dir, candidate.files) {
+				name = candidate.name
+				break
+			}
+		}
+		if name == "" {
+			name = "npm"
+		}
+	}
+
+	manager := nodePackageManager{Name: name}
+	for _, candidate := range packageManagerLockfiles {
+		if candidate.name == name {
+			manager.HasLockfile = hasAnyFile(dir, candidate.files)
+		}
+	}
+
+	if name == "yarn" {
+		major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+		manager.YarnBerry = major >= 2 || fileExists(filepath.Join(dir, ".yarnrc.yml"))
+	}
+	return manager
+}
+
+// installArgs installs the locked versions without touching the lockfile when
+// there is one
+func (m nodePackageManager) installArgs() []string {
+	if !m.HasLockfile {
+		return []string{"install"}
+	}
+
+	switch m.Name {
+	case "npm":
+		return []string{"ci"}
+	case "yarn":
+		if m.YarnBerry {
+			return []string{"install", "--immutable"}
+		}
+		return []string{"install", "--frozen-lockfile"}
+	default:
+		return []string{"install", "--frozen-lockfile"}
+	}
+}
+
+// runArgs runs a package.json script, all package managers understand "run"
+func (m nodePackageManager) runArgs(script string) []string {
+	return []string{"run", script}
+}
+
+func isKnownPackageManager(name string) bool {
+	for _, candidate := range packageManagerLockfiles {
+		if candidate.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyFile(dir string, files []string) bool {
+	for _, file := range files {
+		if fileExists(filepath.Join(dir, file)) {
+			return true
+		}
+	}
+	return false
+}
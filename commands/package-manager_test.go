@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantName    string
+		wantInstall string
+	}{
+		{name: "no lockfile", files: map[string]string{}, wantName: "npm", wantInstall: "install"},
+		{name: "npm lockfile", files: map[string]string{"package-lock.json": "{}"}, wantName: "npm", wantInstall: "ci"},
+		{name: "pnpm lockfile", files: map[string]string{"pnpm-lock.yaml": ""}, wantName: "pnpm", wantInstall: "install --frozen-lockfile"},
+		{name: "yarn classic", files: map[string]string{"yarn.lock": ""}, wantName: "yarn", wantInstall: "install --frozen-lockfile"},
+		{name: "yarn berry by yarnrc", files: map[string]string{"yarn.lock": "", ".yarnrc.yml": ""}, wantName: "yarn", wantInstall: "install --immutable"},
+		{name: "bun binary lockfile", files: map[string]string{"bun.lockb": ""}, wantName: "bun", wantInstall: "install --frozen-lockfile"},
+		{name: "bun text lockfile", files: map[string]string{"bun.lock": ""}, wantName: "bun", wantInstall: "install --frozen-lockfile"},
+		{
+			name:        "packageManager field wins",
+			files:       map[string]string{"package.json": `{"packageManager": "pnpm@9.1.0"}`, "package-lock.json": "{}"},
+			wantName:    "pnpm",
+			wantInstall: "install",
+		},
+		{
+			name:        "yarn berry by packageManager",
+			files:       map[string]string{"package.json": `{"packageManager": "yarn@4.2.2+sha512.abc"}`, "yarn.lock": ""},
+			wantName:    "yarn",
+			wantInstall: "install --immutable",
+		},
+		{
+			name:        "unknown packageManager",
+			files:       map[string]string{"package.json": `{"packageManager": "deno@2.0.0"}`, "yarn.lock": ""},
+			wantName:    "yarn",
+			wantInstall: "install --frozen-lockfile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manager := detectPackageManager(dir)
+			if manager.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", manager.Name, tt.wantName)
+			}
+			if install := strings.Join(manager.installArgs(), " "); install != tt.wantInstall {
+				t.Errorf("installArgs() = %q, want %q", install, tt.wantInstall)
+			}
+		})
+	}
+}
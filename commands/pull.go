@@ -23,6 +23,13 @@ func Pull(cliContext *cli.Context) error {
 		return nil
 	}
 
+	// The import replaces the local database, make sure it really is local
+	if cliContext.Bool("database") {
+		if err := guardDestructive("pull --database", cliContext.Bool("force")); err != nil {
+			return err
+		}
+	}
+
 	if cliContext.Bool("storage") {
 		if err := pullStorage(localEnv); err != nil {
 			return err
@@ -129,13 +136,25 @@ func pullDatabase(env map[string]string) error {
 	localDBUser := cfg.DBUser
 	localDBPassword := cfg.DBPassword
 
+	runtime, err := utils.ProjectRuntime()
+	if err != nil {
+		return err
+	}
+	envManager := utils.NewEnvManager(".env")
+	// The containers create their database user from .env, the global
+	// credentials only work for the mysql on the host
+	inContainer := runtime != utils.RuntimeHost
+	if inContainer {
+		localDBUser, localDBPassword = containerDatabaseCredentials(envManager)
+	}
+
 	fmt.Println("Importing database dump locally...")
 
 	if err := runMySQLCommand(localDBUser, localDBPassword, "", fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", localDBName)); err != nil {
 		return fmt.Errorf("error creating local database: %v", err)
 	}
 
-	if err := runMySQLCommand(localDBUser, localDBPassword, localDBName, fmt.Sprintf("source %s", localPath)); err != nil {
+	if err := importMySQLDump(localDBUser, localDBPassword, localDBName, localPath); err != nil {
 		return fmt.Errorf("error importing database dump locally: %v", err)
 	}
 
@@ -149,7 +168,6 @@ func pullDatabase(env map[string]string) error {
 
 	fmt.Println("Database successfully pulled!")
 
-	envManager := utils.NewEnvManager(".env")
 	if _, exists, err := envManager.GetVar("DB_DATABASE"); !exists {
 		if err != nil {
 			return fmt.Errorf("error checking DB_DATABASE in .env: %v", err)
@@ -159,11 +177,13 @@ func pullDatabase(env map[string]string) error {
 		if err := envManager.SetVar("DB_DATABASE", localDBName); err != nil {
 			return fmt.Errorf("error adding DB_DATABASE to .env: %v", err)
 		}
-		if err := envManager.SetVar("DB_USERNAME", localDBUser); err != nil {
-			return fmt.Errorf("error adding DB_USERNAME to .env: %v", err)
-		}
-		if err := envManager.SetVar("DB_PASSWORD", localDBPassword); err != nil {
-			return fmt.Errorf("error adding DB_PASSWORD to .env: %v", err)
+		if !inContainer {
+			if err := envManager.SetVar("DB_USERNAME", localDBUser); err != nil {
+				return fmt.Errorf("error adding DB_USERNAME to .env: %v", err)
+			}
+			if err := envManager.SetVar("DB_PASSWORD", localDBPassword); err != nil {
+				return fmt.Errorf("error adding DB_PASSWORD to .env: %v", err)
+			}
 		}
 
 		fmt.Println("Database credentials added to local .env file.")
@@ -173,6 +193,20 @@ func pullDatabase(env map[string]string) error {
 	return nil
 }
 
+// containerDatabaseCredentials returns the user and password from .env, which
+// Sail, Compose and DDEV setups use to create the database user. Sail's root
+// password is DB_PASSWORD as well.
+func containerDatabaseCredentials(envManager *utils.EnvManager) (string, string) {
+	user, _, _ := envManager.GetVar("DB_USERNAME")
+	password, _, _ := envManager.GetVar("DB_PASSWORD")
+
+	user = utils.UnquoteValue(user)
+	if user == "" {
+		user = "root"
+	}
+	return user, utils.UnquoteValue(password)
+}
+
 func runMySQLCommand(user, password, dbName, query string) error {
 	args := []string{"-u", user}
 	if password != "" {
@@ -182,7 +216,29 @@ func runMySQLCommand(user, password, dbName, query string) error {
 		args = append(args, dbName)
 	}
 	args = append(args, "-e", query)
-	return utils.RunCommand("mysql", args...)
+	return utils.RunProjectCommand("mysql", args...)
+}
+
+// importMySQLDump feeds the dump through stdin, so it also works when mysql runs in a container
+func importMySQLDump(user, password, dbName, dumpPath string) error {
+	dump, err := os.Open(dumpPath)
+	if err != nil {
+		return err
+	}
+	defer dump.Close()
+
+	args := []string{"-u", user}
+	if password != "" {
+		args = append(args, "-p"+password)
+	}
+	args = append(args, dbName)
+
+	cmd, err := utils.ProjectCommand("mysql", args...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = dump
+	return cmd.Run()
 }
 
 func getRemoteEnvValue(env map[string]string, remoteEnvPath, key, context string) (string, error) {
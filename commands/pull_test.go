@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mo/utils"
+)
+
+func TestContainerDatabaseCredentials(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          string
+		wantUser     string
+		wantPassword string
+	}{
+		{name: "sail", env: "DB_USERNAME=sail\nDB_PASSWORD=\"password\"\n", wantUser: "sail", wantPassword: "password"},
+		{name: "no user", env: "DB_PASSWORD=secret\n", wantUser: "root", wantPassword: "secret"},
+		{name: "empty", env: "", wantUser: "root", wantPassword: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte(tt.env), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			user, password := containerDatabaseCredentials(utils.NewEnvManager(path))
+			if user != tt.wantUser || password != tt.wantPassword {
+				t.Errorf("got %q, %q, want %q, %q", user, password, tt.wantUser, tt.wantPassword)
+			}
+		})
+	}
+}
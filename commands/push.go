@@ -167,13 +167,7 @@ func pushDatabase(env map[string]string) error {
 
 	dumpFile := fmt.Sprintf("%s-dump.sql", localDBName)
 
-	var dumpCmd string
-	if localDBPassword != "" {
-		dumpCmd = fmt.Sprintf("mysqldump -u %s -p%s %s > %s", localDBUser, localDBPassword, localDBName, dumpFile)
-	} else {
-		dumpCmd = fmt.Sprintf("mysqldump -u %s %s > %s", localDBUser, localDBName, dumpFile)
-	}
-	if err := utils.RunCommand("bash", "-c", dumpCmd); err != nil {
+	if err := dumpLocalDatabase(localDBUser, localDBPassword, localDBName, dumpFile); err != nil {
 		return fmt.Errorf("error creating local database dump: %v", err)
 	}
 
@@ -208,3 +202,26 @@ func pushDatabase(env map[string]string) error {
 	fmt.Println("Database successfully pushed!")
 	return nil
 }
+
+// dumpLocalDatabase runs mysqldump in the project's runtime and writes the dump to dumpFile
+func dumpLocalDatabase(user, password, dbName, dumpFile string) error {
+	args := []string{"-u", user}
+	if password != "" {
+		args = append(args, "-p"+password)
+	}
+	args = append(args, dbName)
+
+	dump, err := os.Create(dumpFile)
+	if err != nil {
+		return err
+	}
+	defer dump.Close()
+
+	cmd, err := utils.ProjectCommand("mysqldump", args...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = nil
+	cmd.Stdout = dump
+	return cmd.Run()
+}
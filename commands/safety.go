@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"mo/config"
+	"mo/utils"
+)
+
+// localEnvironments are the APP_ENV values destructive commands run in without --force
+var localEnvironments = []string{"local", "testing", "development", "dev"}
+
+// localDatabaseHosts are the loopback addresses, container service names and
+// local TLDs destructive commands run against without --force
+var localDatabaseHosts = []string{"", "localhost", "127.0.0.1", "::1", "mysql", "mariadb", "db", "database", "pgsql", "*.test", "*.local", "*.localhost"}
+
+// guardDestructive refuses to run a command that destroys data when .env
+// points at a non-local environment or database host, unless force is set
+func guardDestructive(action string, force bool) error {
+	envManager := utils.NewEnvManager(".env")
+	values := databaseSettings(envManager)
+	appEnv, found, _ := envManager.GetVar("APP_ENV")
+	if found {
+		appEnv = utils.UnquoteValue(appEnv)
+	}
+
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	allowedEnvs := append([]string{}, localEnvironments...)
+	allowedHosts := append([]string{}, localDatabaseHosts...)
+	for _, safety := range []*config.SafetyConfig{cfg.Safety, projectConfig.Safety} {
+		if safety != nil {
+			allowedEnvs = append(allowedEnvs, safety.AllowedEnvs...)
+			allowedHosts = append(allowedHosts, safety.AllowedHosts...)
+		}
+	}
+
+	reasons := unsafeReasons(appEnv, found, values["DB_CONNECTION"], values["DB_HOST"], allowedEnvs, allowedHosts)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	if force {
+		fmt.Printf("⚠ %s, running %s anyway because of --force\n", strings.Join(reasons, " and "), action)
+		return nil
+	}
+	return fmt.Errorf("refusing to run %s: %s. Use --force to run it anyway, or allow them under \"safety\" in %s",
+		action, strings.Join(reasons, " and "), config.ProjectConfigFile)
+}
+
+// unsafeReasons explains why the environment is not local. A missing APP_ENV
+// counts as production, Laravel's default.
+func unsafeReasons(appEnv string, appEnvFound bool, connection, host string, allowedEnvs, allowedHosts []string) []string {
+	var reasons []string
+
+	if !appEnvFound {
+		reasons = append(reasons, "APP_ENV is not set (Laravel defaults to production)")
+	} else if !matchesAnyPattern(strings.ToLower(appEnv), allowedEnvs) {
+		reasons = append(reasons, fmt.Sprintf("APP_ENV is %q", appEnv))
+	}
+
+	if connection != "sqlite" && !matchesAnyPattern(strings.ToLower(host), allowedHosts) {
+		reasons = append(reasons, fmt.Sprintf("DB_HOST %q is not a local host", host))
+	}
+
+	return reasons
+}
+
+func matchesAnyPattern(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), value); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUnsafeReasons(t *testing.T) {
+	tests := []struct {
+		name        string
+		appEnv      string
+		appEnvFound bool
+		connection  string
+		host        string
+		want        []string
+	}{
+		{name: "local mysql", appEnv: "local", appEnvFound: true, connection: "mysql", host: "127.0.0.1"},
+		{name: "sail", appEnv: "local", appEnvFound: true, connection: "mysql", host: "mysql"},
+		{name: "valet host", appEnv: "Local", appEnvFound: true, connection: "mysql", host: "shop.test"},
+		{name: "sqlite ignores host", appEnv: "testing", appEnvFound: true, connection: "sqlite", host: "db.example.com"},
+		{name: "production", appEnv: "production", appEnvFound: true, connection: "mysql", host: "127.0.0.1", want: []string{`APP_ENV is "production"`}},
+		{name: "missing env", connection: "mysql", host: "localhost", want: []string{"APP_ENV is not set"}},
+		{name: "remote host", appEnv: "local", appEnvFound: true, connection: "mysql", host: "db.example.com", want: []string{`DB_HOST "db.example.com"`}},
+		{name: "both", appEnv: "staging", appEnvFound: true, connection: "pgsql", host: "10.0.0.5", want: []string{"APP_ENV", "DB_HOST"}},
+		{name: "allowed by pattern", appEnv: "local", appEnvFound: true, connection: "mysql", host: "192.168.56.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowedHosts := append(append([]string{}, localDatabaseHosts...), "192.168.56.*")
+			reasons := unsafeReasons(tt.appEnv, tt.appEnvFound, tt.connection, tt.host, localEnvironments, allowedHosts)
+			if len(reasons) != len(tt.want) {
+				t.Fatalf("got %q, want %d reasons containing %q", reasons, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(reasons[i], want) {
+					t.Errorf("reason %d = %q, want it to contain %q", i, reasons[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGuardDestructive(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", dir)
+
+	if err := os.WriteFile(".env", []byte("APP_ENV=production\nDB_CONNECTION=mysql\nDB_HOST=db.internal\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := guardDestructive("migrate:fresh", false)
+	if err == nil || !strings.Contains(err.Error(), "refusing to run migrate:fresh") {
+		t.Fatalf("expected a refusal, got %v", err)
+	}
+	if err := guardDestructive("migrate:fresh", true); err != nil {
+		t.Errorf("--force should pass, got %v", err)
+	}
+
+	if err := os.WriteFile(".mo.json", []byte(`{"safety": {"allowed_envs": ["production"], "allowed_hosts": ["*.internal"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := guardDestructive("migrate:fresh", false); err != nil {
+		t.Errorf("configured env and host should pass, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+
+	"mo/config"
+	"mo/utils"
+)
+
+type setupStepResult int
+
+const (
+	setupStepPassed setupStepResult = iota
+	setupStepFailed
+	setupStepSkipped
+)
+
+// runSetupPipeline runs the setup steps from .mo.json in order. A failing step
+// stops the pipeline unless it has continue_on_error.
+func runSetupPipeline(steps []config.SetupStep) error {
+	commands := make([][]string, len(steps))
+	for i, step := range steps {
+		args, err := splitCommandLine(step.Run)
+		if err != nil {
+			return fmt.Errorf("error parsing setup step %d: %v", i+1, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("setup step %d has nothing to run", i+1)
+		}
+		commands[i] = args
+	}
+
+	results := make([]setupStepResult, len(steps))
+	for i, step := range steps {
+		name := setupStepName(step)
+
+		if reason := setupSkipReason(step.If); reason != "" {
+			log.Printf("[%d/%d] Skipping %s: %s", i+1, len(steps), name, reason)
+			results[i] = setupStepSkipped
+			continue
+		}
+
+		log.Printf("[%d/%d] %s", i+1, len(steps), name)
+		if err := runSetupStep(commands[i], step.Env); err != nil {
+			if !step.ContinueOnError {
+				return fmt.Errorf("setup step %q failed: %w", name, err)
+			}
+			log.Printf("%s failed, continuing: %v", name, err)
+			results[i] = setupStepFailed
+		}
+	}
+
+	fmt.Println("----------------------------")
+	for i, step := range steps {
+		switch results[i] {
+		case setupStepPassed:
+			fmt.Printf("✓ %s\n", setupStepName(step))
+		case setupStepFailed:
+			fmt.Printf("✗ %s (continued)\n", setupStepName(step))
+		case setupStepSkipped:
+			fmt.Printf("- %s (skipped)\n", setupStepName(step))
+		}
+	}
+	fmt.Println("----------------------------")
+	return nil
+}
+
+func setupStepName(step config.SetupStep) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return step.Run
+}
+
+// setupSkipReason returns why the condition doesn't hold, or "" if the step
+// should run. .env is read for every step since earlier steps may create it.
+func setupSkipReason(condition *config.SetupCondition) string {
+	if condition == nil {
+		return ""
+	}
+
+	if condition.FileExists != "" && !fileExists(condition.FileExists) {
+		return fmt.Sprintf("%s not found", condition.FileExists)
+	}
+	if condition.FileMissing != "" && fileExists(condition.FileMissing) {
+		return fmt.Sprintf("%s exists", condition.FileMissing)
+	}
+
+	envManager := utils.NewEnvManager(".env")
+	if condition.EnvSet != "" {
+		if value, _, _ := envManager.GetVar(condition.EnvSet); utils.UnquoteValue(value) == "" {
+			return fmt.Sprintf("%s is not set in .env", condition.EnvSet)
+		}
+	}
+	if condition.EnvMissing != "" {
+		if value, _, _ := envManager.GetVar(condition.EnvMissing); utils.UnquoteValue(value) != "" {
+			return fmt.Sprintf("%s is already set in .env", condition.EnvMissing)
+		}
+	}
+	return ""
+}
+
+// runSetupStep runs php, composer and npm in the project's runtime like the
+// auto-detected steps, with env passed into the container. "mo" runs this
+// binary so built-in commands work as steps.
+func runSetupStep(args []string, env map[string]string) error {
+	if args[0] != "mo" {
+		cmd, err := utils.ProjectCommandWithEnv(env, args[0], args[1:]...)
+		if err != nil {
+			return err
+		}
+		return cmd.Run()
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error finding the mo binary: %v", err)
+	}
+	cmd := exec.Command(executable, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), utils.EnvPairs(env)...)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"mo/config"
+)
+
+func TestSetupSkipReason(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile("composer.json", []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".env", []byte("APP_KEY=\nDB_DATABASE=shop\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		condition *config.SetupCondition
+		want      string
+	}{
+		{name: "no condition", condition: nil, want: ""},
+		{name: "file exists", condition: &config.SetupCondition{FileExists: "composer.json"}, want: ""},
+		{name: "file not found", condition: &config.SetupCondition{FileExists: "package.json"}, want: "package.json not found"},
+		{name: "file missing", condition: &config.SetupCondition{FileMissing: "composer.json"}, want: "composer.json exists"},
+		{name: "env set", condition: &config.SetupCondition{EnvSet: "DB_DATABASE"}, want: ""},
+		{name: "env empty", condition: &config.SetupCondition{EnvSet: "APP_KEY"}, want: "APP_KEY is not set in .env"},
+		{name: "env missing", condition: &config.SetupCondition{EnvMissing: "APP_KEY"}, want: ""},
+		{name: "env already set", condition: &config.SetupCondition{EnvMissing: "DB_DATABASE"}, want: "DB_DATABASE is already set in .env"},
+		{name: "all must hold", condition: &config.SetupCondition{FileExists: "composer.json", EnvSet: "REDIS_HOST"}, want: "REDIS_HOST is not set in .env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setupSkipReason(tt.condition); got != tt.want {
+				t.Errorf("setupSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSetupPipeline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", dir)
+
+	steps := []config.SetupStep{
+		{Name: "first", Run: "sh -c 'echo first >> steps.log'"},
+		{Name: "skipped", Run: "sh -c 'echo skipped >> steps.log'", If: &config.SetupCondition{FileExists: "artisan"}},
+		{Name: "allowed to fail", Run: "sh -c 'exit 1'", ContinueOnError: true},
+		{Name: "with env", Run: `sh -c 'echo "$GREETING" >> steps.log'`, Env: map[string]string{"GREETING": "hello from setup"}},
+	}
+	if err := runSetupPipeline(steps); err != nil {
+		t.Fatalf("runSetupPipeline() error = %v", err)
+	}
+
+	content, err := os.ReadFile("steps.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "first\nhello from setup\n" {
+		t.Errorf("steps.log = %q", content)
+	}
+
+	steps = []config.SetupStep{
+		{Name: "breaks", Run: "sh -c 'exit 2'"},
+		{Name: "never runs", Run: "sh -c 'echo never >> steps.log'"},
+	}
+	err = runSetupPipeline(steps)
+	if err == nil || !strings.Contains(err.Error(), `"breaks"`) {
+		t.Fatalf("expected the failing step in the error, got %v", err)
+	}
+	if content, _ := os.ReadFile("steps.log"); strings.Contains(string(content), "never") {
+		t.Error("the pipeline continued after a failing step")
+	}
+
+	if err := runSetupPipeline([]config.SetupStep{{Name: "empty"}}); err == nil {
+		t.Error("expected an error for a step without a command")
+	}
+}
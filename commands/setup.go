@@ -8,12 +8,22 @@ import (
 	"os"
 	"strings"
 
+	"mo/config"
 	"mo/utils"
 
 	"github.com/urfave/cli/v2"
 )
 
 func CheckProject(cliContext *cli.Context) error {
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return err
+	}
+	if len(projectConfig.Setup) > 0 {
+		log.Printf("Running the setup steps from %s...", config.ProjectConfigFile)
+		return runSetupPipeline(projectConfig.Setup)
+	}
+
 	if err := handleComposer(); err != nil {
 		return err
 	}
@@ -32,7 +42,7 @@ func CheckProject(cliContext *cli.Context) error {
 func handleComposer() error {
 	if fileExists("composer.json") {
 		log.Println("composer.json found, running composer install...")
-		if err := utils.RunCommand("composer", "install"); err != nil {
+		if err := utils.RunProjectCommand("composer", "install"); err != nil {
 			return fmt.Errorf("composer install failed: %w", err)
 		}
 	} else {
@@ -59,17 +69,17 @@ func handleLaravel() error {
 	}
 	if !hasAppKey {
 		log.Println("No APP_KEY found, generating one...")
-		if err := utils.RunCommand("php", "artisan", "key:generate"); err != nil {
-			return fmt.Errorf("key:generate failed: %w", err)
+		if _, err := generateAppKey(utils.NewEnvManager(".env"), appCipher(), false); err != nil {
+			return fmt.Errorf("generating APP_KEY failed: %w", err)
 		}
 	} else {
-		log.Println("APP_KEY already exists, skipping key:generate")
+		log.Println("APP_KEY already exists, skipping key generation")
 	}
 
-	if err := utils.RunCommand("php", "artisan", "migrate"); err != nil {
+	if err := utils.RunProjectCommand("php", "artisan", "migrate"); err != nil {
 		return fmt.Errorf("artisan migrate failed: %w", err)
 	}
-	if err := utils.RunCommand("php", "artisan", "db:seed"); err != nil {
+	if err := utils.RunProjectCommand("php", "artisan", "db:seed"); err != nil {
 		return fmt.Errorf("artisan db:seed failed: %w", err)
 	}
 
@@ -82,9 +92,11 @@ func handleNode() error {
 		return nil
 	}
 
-	log.Println("package.json found, running npm install...")
-	if err := utils.RunCommand("npm", "install"); err != nil {
-		return fmt.Errorf("npm install failed: %w", err)
+	manager := detectPackageManager(".")
+	installArgs := manager.installArgs()
+	log.Printf("package.json found, running %s %s...", manager.Name, strings.Join(installArgs, " "))
+	if err := utils.RunProjectCommand(manager.Name, installArgs...); err != nil {
+		return fmt.Errorf("%s install failed: %w", manager.Name, err)
 	}
 
 	hasBuildScript, err := hasNpmScript("build")
@@ -92,9 +104,9 @@ func handleNode() error {
 		return fmt.Errorf("error checking build script: %w", err)
 	}
 	if hasBuildScript {
-		log.Println("Build script found, running npm run build...")
-		if err := utils.RunCommand("npm", "run", "build"); err != nil {
-			return fmt.Errorf("npm run build failed: %w", err)
+		log.Printf("Build script found, running %s run build...", manager.Name)
+		if err := utils.RunProjectCommand(manager.Name, manager.runArgs("build")...); err != nil {
+			return fmt.Errorf("%s run build failed: %w", manager.Name, err)
 		}
 	} else {
 		log.Println("No build script found")
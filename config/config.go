@@ -1,7 +1,9 @@
 package config
 
 import (
+	"crypto/sha1"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -15,6 +17,10 @@ type Config struct {
 	MailtrapUsername string            `json:"mailtrap_username"`
 	MailtrapPassword string            `json:"mailtrap_password"`
 	ConfigPaths      map[string]string `json:"config_paths"`
+	LaravelClear     []string          `json:"laravel_clear,omitempty"`
+	LaravelCache     []string          `json:"laravel_cache,omitempty"`
+	Runtime          string            `json:"runtime,omitempty"`
+	Safety           *SafetyConfig     `json:"safety,omitempty"`
 }
 
 func DefaultConfig() *Config {
@@ -66,6 +72,26 @@ func ConfigPath() (string, error) {
 	return configPathFunc()
 }
 
+// ProjectDataDir returns the directory where mo keeps state for the project in
+// projectDir (e.g. remembered database credentials). It lives next to the config
+// file so nothing ends up in the project's repository.
+func ProjectDataDir(projectDir string) (string, error) {
+	configPath, err := configPathFunc()
+	if err != nil {
+		return "", err
+	}
+
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha1.Sum([]byte(absDir))
+	name := fmt.Sprintf("%s-%x", filepath.Base(absDir), hash[:6])
+
+	return filepath.Join(filepath.Dir(configPath), "projects", name), nil
+}
+
 func defaultConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -168,3 +168,67 @@ func TestConfigPath(t *testing.T) {
 		t.Errorf("ConfigPath() should end with config.json, got %v", path)
 	}
 }
+
+func TestProjectDataDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	originalConfigPath := configPathFunc
+	configPathFunc = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+	defer func() { configPathFunc = originalConfigPath }()
+
+	first, err := ProjectDataDir("/projects/shop")
+	if err != nil {
+		t.Fatalf("ProjectDataDir() error = %v", err)
+	}
+	second, _ := ProjectDataDir("/other/shop")
+
+	if filepath.Dir(first) != filepath.Join(tmpDir, "projects") {
+		t.Errorf("ProjectDataDir() = %v, want a directory below %v", first, filepath.Join(tmpDir, "projects"))
+	}
+	if first == second {
+		t.Errorf("ProjectDataDir() returned the same directory for different projects: %v", first)
+	}
+}
+
+func TestLoadProjectConfig(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		projectConfig, err := LoadProjectConfig(t.TempDir())
+		if err != nil {
+			t.Fatalf("LoadProjectConfig() error = %v", err)
+		}
+		if projectConfig.EnvSync != nil {
+			t.Error("EnvSync should be nil without a .mo.json")
+		}
+	})
+
+	t.Run("env sync targets", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		content := `{"env_sync": {"source": ".env.example", "targets": [{"file": ".env.testing", "ignore": ["DUSK_*"]}]}}`
+		if err := os.WriteFile(filepath.Join(tmpDir, ProjectConfigFile), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		projectConfig, err := LoadProjectConfig(tmpDir)
+		if err != nil {
+			t.Fatalf("LoadProjectConfig() error = %v", err)
+		}
+		if projectConfig.EnvSync == nil || projectConfig.EnvSync.Source != ".env.example" {
+			t.Fatalf("EnvSync not loaded correctly: %+v", projectConfig.EnvSync)
+		}
+		if len(projectConfig.EnvSync.Targets) != 1 || projectConfig.EnvSync.Targets[0].Ignore[0] != "DUSK_*" {
+			t.Errorf("Targets not loaded correctly: %+v", projectConfig.EnvSync.Targets)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ProjectConfigFile), []byte("{invalid"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadProjectConfig(tmpDir); err == nil {
+			t.Error("Expected error for invalid JSON, got nil")
+		}
+	})
+}
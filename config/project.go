@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFile is the per-project config file, read from the project root
+const ProjectConfigFile = ".mo.json"
+
+// ProjectConfig holds settings that only apply to a single project
+type ProjectConfig struct {
+	EnvSync       *EnvSyncConfig              `json:"env_sync,omitempty"`
+	EnvFromRemote map[string]EnvRemoteProfile `json:"env_from_remote,omitempty"`
+	EnvTesting    *EnvTestingConfig           `json:"env_testing,omitempty"`
+	LaravelClear  []string                    `json:"laravel_clear,omitempty"`
+	LaravelCache  []string                    `json:"laravel_cache,omitempty"`
+	Runtime       string                      `json:"runtime,omitempty"`
+	Dev           []DevProcess                `json:"dev,omitempty"`
+	Safety        *SafetyConfig               `json:"safety,omitempty"`
+	Setup         []SetupStep                 `json:"setup,omitempty"`
+}
+
+// EnvSyncConfig describes which env files env:sync reconciles
+type EnvSyncConfig struct {
+	Source  string          `json:"source"`
+	Targets []EnvSyncTarget `json:"targets"`
+}
+
+// EnvSyncTarget is a file that receives missing keys from the source.
+// Ignore accepts exact keys or glob patterns such as "DUSK_*".
+type EnvSyncTarget struct {
+	File       string   `json:"file"`
+	Ignore     []string `json:"ignore,omitempty"`
+	CopyValues bool     `json:"copy_values,omitempty"`
+}
+
+// EnvRemoteProfile describes where env:from-remote downloads a .env from and
+// how it is rewritten for local use. Connection fields default to PULL_* from .env.
+type EnvRemoteProfile struct {
+	SSHUser    string            `json:"ssh_user,omitempty"`
+	Host       string            `json:"host,omitempty"`
+	ProjectDir string            `json:"project_dir,omitempty"`
+	Set        map[string]string `json:"set,omitempty"`
+	Drop       []string          `json:"drop,omitempty"`
+	Presets    []string          `json:"presets,omitempty"`
+}
+
+// EnvTestingConfig customizes the .env.testing env:testing derives from .env.
+// Database is "sqlite" (in memory) or "mysql" (a separate <db>_test database).
+type EnvTestingConfig struct {
+	Database string            `json:"database,omitempty"`
+	Set      map[string]string `json:"set,omitempty"`
+	Drop     []string          `json:"drop,omitempty"`
+}
+
+// DevProcess is a long running process `mo dev` starts, such as a queue worker.
+// Crashed processes are restarted unless Restart is false.
+type DevProcess struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	Restart *bool  `json:"restart,omitempty"`
+}
+
+// SafetyConfig allows more environments and database hosts for destructive
+// commands such as l:fresh. Both accept glob patterns like "*.internal".
+type SafetyConfig struct {
+	AllowedEnvs  []string `json:"allowed_envs,omitempty"`
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+}
+
+// SetupStep is a command `mo setup` runs instead of the auto-detected steps.
+// Run is split like a shell would but not run through one, "mo" runs mo itself.
+type SetupStep struct {
+	Name            string            `json:"name,omitempty"`
+	Run             string            `json:"run"`
+	If              *SetupCondition   `json:"if,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	ContinueOnError bool              `json:"continue_on_error,omitempty"`
+}
+
+// SetupCondition skips a setup step unless all of its fields hold. The env
+// keys are looked up in .env.
+type SetupCondition struct {
+	FileExists  string `json:"file_exists,omitempty"`
+	FileMissing string `json:"file_missing,omitempty"`
+	EnvSet      string `json:"env_set,omitempty"`
+	EnvMissing  string `json:"env_missing,omitempty"`
+}
+
+// LoadProjectConfig reads .mo.json from projectDir. A missing file is not an
+// error, an empty config is returned instead.
+func LoadProjectConfig(projectDir string) (*ProjectConfig, error) {
+	projectConfig := &ProjectConfig{}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, ProjectConfigFile))
+	if os.IsNotExist(err) {
+		return projectConfig, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, projectConfig); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", ProjectConfigFile, err)
+	}
+
+	return projectConfig, nil
+}
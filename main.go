@@ -3,9 +3,12 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"mo/commands"
 	"mo/config"
+	"mo/utils"
 
 	"github.com/urfave/cli/v2"
 )
@@ -39,10 +42,17 @@ func main() {
 
 	envSqliteCmd := &cli.Command{
 		Name:   "sqlite",
-		Usage:  "Set the DB_CONNECTION to sqlite",
+		Usage:  "Switch the database to sqlite (shortcut for env:db sqlite)",
 		Action: commands.EnvSqlite,
 	}
 
+	envDBCmd := &cli.Command{
+		Name:      "db",
+		Usage:     "Switch the database driver (sqlite, mysql, pgsql)",
+		ArgsUsage: "<sqlite|mysql|pgsql>",
+		Action:    commands.EnvDB,
+	}
+
 	envMailtrapCmd := &cli.Command{
 		Name:   "mailtrap",
 		Usage:  "Set the mail driver to mailtrap",
@@ -56,9 +66,282 @@ func main() {
 	}
 
 	envSyncCmd := &cli.Command{
-		Name:   "sync",
-		Usage:  "Sync the .env file with .env.example",
-		Action: commands.SyncEnv,
+		Name:      "sync",
+		Usage:     "Sync the .env file with .env.example (or other targets)",
+		ArgsUsage: "[target files...]",
+		Action:    commands.SyncEnv,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "source",
+				Usage: "File to take the keys from (default: .env)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "ignore",
+				Usage: "Keys (or patterns like DUSK_*) to leave out of the targets",
+			},
+			&cli.BoolFlag{
+				Name:  "with-values",
+				Usage: "Copy values from the source instead of adding empty keys",
+			},
+		},
+	}
+
+	envCryptFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "key",
+			Usage: "The encryption key (base64:... or raw)",
+		},
+		&cli.StringFlag{
+			Name:  "cipher",
+			Usage: "The encryption cipher",
+			Value: utils.DefaultCipher,
+		},
+		&cli.StringFlag{
+			Name:  "env",
+			Usage: "The environment to be encrypted/decrypted (e.g. staging for .env.staging)",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Overwrite existing files",
+		},
+	}
+
+	envEncryptCmd := &cli.Command{
+		Name:   "encrypt",
+		Usage:  "Encrypt an environment file (compatible with artisan env:encrypt)",
+		Action: commands.EnvEncrypt,
+		Flags:  envCryptFlags,
+	}
+
+	envDecryptCmd := &cli.Command{
+		Name:   "decrypt",
+		Usage:  "Decrypt an environment file (compatible with artisan env:decrypt)",
+		Action: commands.EnvDecrypt,
+		Flags: append(envCryptFlags, &cli.StringFlag{
+			Name:  "filename",
+			Usage: "Write the decrypted file to this path instead",
+		}),
+	}
+
+	envKeyCmd := &cli.Command{
+		Name:   "key",
+		Usage:  "Generate APP_KEY without PHP (like artisan key:generate)",
+		Action: commands.EnvKey,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite an existing APP_KEY",
+			},
+			&cli.BoolFlag{
+				Name:  "show",
+				Usage: "Display the key instead of writing it",
+			},
+			&cli.StringFlag{
+				Name:  "env",
+				Usage: "Write to .env.<env> instead of .env",
+			},
+		},
+	}
+
+	envScanCmd := &cli.Command{
+		Name:   "scan",
+		Usage:  "Find env() keys used in config/, app/ and routes/ that are missing from .env.example",
+		Action: commands.EnvScan,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "add",
+				Usage: "Add missing keys to .env.example using the defaults found in code",
+			},
+			&cli.BoolFlag{
+				Name:  "defaults",
+				Usage: "List all defaults found in code",
+			},
+		},
+	}
+
+	envExampleCmd := &cli.Command{
+		Name:   "example",
+		Usage:  "Generate a sanitized .env.example from .env",
+		Action: commands.EnvExample,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "source",
+				Usage: "The env file to read",
+				Value: ".env",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "The file to write",
+				Value: ".env.example",
+			},
+			&cli.StringFlag{
+				Name:  "placeholder",
+				Usage: "Value used for secrets ({key} is replaced with the key name)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "secret",
+				Usage: "Additional key patterns to treat as secrets (e.g. *_DSN)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite an existing output file",
+			},
+			&cli.BoolFlag{
+				Name:  "stdout",
+				Usage: "Print the result instead of writing it",
+			},
+		},
+	}
+
+	envHistoryCmd := &cli.Command{
+		Name:   "history",
+		Usage:  "Show the changes mo made to .env files in this project",
+		Action: commands.EnvHistory,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Only show the latest n changes",
+			},
+			&cli.BoolFlag{
+				Name:  "show-values",
+				Usage: "Show secret values instead of masking them",
+			},
+		},
+	}
+
+	envUndoCmd := &cli.Command{
+		Name:      "undo",
+		Usage:     "Roll back the last n changes mo made to .env files (default 1)",
+		ArgsUsage: "[n]",
+		Action:    commands.EnvUndo,
+	}
+
+	envFromRemoteCmd := &cli.Command{
+		Name:      "from-remote",
+		Usage:     "Create .env from a remote server's .env, rewritten for local use",
+		ArgsUsage: "[profile]",
+		Action:    commands.EnvFromRemote,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "The file to write",
+				Value: ".env",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the summary without writing anything",
+			},
+		},
+	}
+
+	envLintCmd := &cli.Command{
+		Name:      "lint",
+		Usage:     "Report duplicate keys, bad spacing, lowercase keys and unquoted values",
+		ArgsUsage: "[files...]",
+		Action:    commands.EnvLint,
+	}
+
+	envFmtCmd := &cli.Command{
+		Name:      "fmt",
+		Usage:     "Fix the problems env:lint reports in place",
+		ArgsUsage: "[files...]",
+		Action:    commands.EnvFmt,
+	}
+
+	envTestingCmd := &cli.Command{
+		Name:   "testing",
+		Usage:  "Derive a .env.testing for fast test runs from .env",
+		Action: commands.EnvTesting,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "database",
+				Usage: "sqlite (in memory) or mysql (a separate <db>_test database)",
+			},
+			&cli.StringFlag{
+				Name:  "source",
+				Usage: "The env file to derive from",
+				Value: ".env",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "The file to write",
+				Value: ".env.testing",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite an existing output file",
+			},
+		},
+	}
+
+	envGuardCmd := &cli.Command{
+		Name:   "guard",
+		Usage:  "Check that no .env secrets are tracked or staged in git",
+		Action: commands.EnvGuard,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "install-hook",
+				Usage: "Install a git pre-commit hook that runs the check",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Replace an existing pre-commit hook",
+			},
+		},
+	}
+
+	mailTestCmd := &cli.Command{
+		Name:      "test",
+		Usage:     "Send a test email using the MAIL_* settings from .env",
+		ArgsUsage: "<to>",
+		Action:    commands.MailTest,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "verbose",
+				Aliases: []string{"v"},
+				Usage:   "Print the SMTP conversation even when sending succeeds",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Connection timeout",
+				Value: 15 * time.Second,
+			},
+		},
+	}
+
+	mailCatchCmd := &cli.Command{
+		Name:   "catch",
+		Usage:  "Run a local SMTP server with a web inbox (works with env:maildev)",
+		Action: commands.MailCatch,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "ip",
+				Usage: "IP address to bind to",
+				Value: "127.0.0.1",
+			},
+			&cli.StringFlag{
+				Name:  "smtp-port",
+				Usage: "SMTP port",
+				Value: "2525",
+			},
+			&cli.StringFlag{
+				Name:  "web-port",
+				Usage: "Web inbox port",
+				Value: "1080",
+			},
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Store messages as .eml files in this directory instead of memory only",
+			},
+		},
+	}
+
+	laravelCommandFlags := []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "continue-on-error",
+			Usage: "Run all commands even if one fails and print a summary",
+		},
 	}
 
 	laravelClearCmd := &cli.Command{
@@ -66,6 +349,127 @@ func main() {
 		Aliases: []string{"c"},
 		Usage:   "Clear all Laravel caches (cache, route, config, view)",
 		Action:  commands.LaravelClear,
+		Flags:   laravelCommandFlags,
+	}
+
+	laravelCacheCmd := &cli.Command{
+		Name:   "cache",
+		Usage:  "Build the Laravel caches (config, route, view, event)",
+		Action: commands.LaravelCache,
+		Flags:  laravelCommandFlags,
+	}
+
+	laravelLogsCmd := &cli.Command{
+		Name:      "logs",
+		Usage:     "Show and filter entries from storage/logs/laravel.log",
+		ArgsUsage: "[files...]",
+		Action:    commands.LaravelLogs,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Keep printing new entries",
+			},
+			&cli.IntFlag{
+				Name:    "lines",
+				Aliases: []string{"n"},
+				Usage:   "Number of entries to show (0 for all)",
+				Value:   20,
+			},
+			&cli.StringFlag{
+				Name:  "level",
+				Usage: "Minimum level (debug, info, notice, warning, error, critical, alert, emergency)",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only entries since a duration (2h) or time (2024-05-01, \"2024-05-01 14:00\")",
+			},
+			&cli.StringFlag{
+				Name:  "grep",
+				Usage: "Only entries whose message, context or exception match (case-insensitive regex)",
+			},
+			&cli.BoolFlag{
+				Name:  "trace",
+				Usage: "Show stack traces instead of folding them",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print entries as JSON lines",
+			},
+		},
+	}
+
+	laravelRoutesCmd := &cli.Command{
+		Name:      "routes",
+		Usage:     "Search the routes of the app (fuzzy matches method, URI, name, action and middleware)",
+		ArgsUsage: "[query]",
+		Action:    commands.LaravelRoutes,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "method",
+				Usage: "Only routes for this HTTP method",
+			},
+			&cli.BoolFlag{
+				Name:  "open",
+				Usage: "Open the controller of the best match in the configured editor",
+			},
+		},
+	}
+
+	laravelDoctorCmd := &cli.Command{
+		Name:   "doctor",
+		Usage:  "Check that the Laravel app can actually run (key, storage, permissions, database, extensions, ...)",
+		Action: commands.LaravelDoctor,
+	}
+
+	laravelMigrationsCmd := &cli.Command{
+		Name:   "migrations",
+		Usage:  "Compare migration files with the migrations table and flag destructive pending migrations",
+		Action: commands.LaravelMigrations,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "pending",
+				Usage: "Hide migrations that already ran",
+			},
+		},
+	}
+
+	laravelFailedCmd := &cli.Command{
+		Name:   "failed",
+		Usage:  "List failed queue jobs from the failed_jobs table",
+		Action: commands.LaravelFailed,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "queue",
+				Usage: "Only jobs of this queue",
+			},
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Number of jobs to show, newest first (0 for all)",
+				Value:   20,
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "show",
+				Usage:     "Show the payload and full exception of a failed job",
+				ArgsUsage: "<uuid>",
+				Action:    commands.LaravelFailedShow,
+			},
+			{
+				Name:      "retry",
+				Usage:     "Push failed jobs back onto the queue (php artisan queue:retry)",
+				ArgsUsage: "<uuid...|all>",
+				Action:    commands.LaravelFailedRetry,
+			},
+			{
+				Name:      "forget",
+				Usage:     "Delete failed jobs (php artisan queue:forget)",
+				ArgsUsage: "<uuid...>",
+				Action:    commands.LaravelFailedForget,
+			},
+		},
 	}
 
 	laravelFreshCmd := &cli.Command{
@@ -78,6 +482,14 @@ func main() {
 				Name:  "no-seed",
 				Usage: "Skip database seeding",
 			},
+			&cli.BoolFlag{
+				Name:  "wipe",
+				Usage: "Only drop all tables (db:wipe) without migrating",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Run even if APP_ENV or DB_HOST are not local",
+			},
 		},
 	}
 
@@ -123,6 +535,23 @@ func main() {
 	}
 
 	app := &cli.App{
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "host",
+				Usage:   "Run php, composer, npm and mysql on the host instead of a detected Sail, Docker Compose or DDEV container",
+				EnvVars: []string{"MO_HOST"},
+			},
+		},
+		Before: func(cliContext *cli.Context) error {
+			// Record every .env change so it can be undone with env:undo
+			if cliContext.Args().Present() {
+				utils.EnableEnvJournal(strings.Join(cliContext.Args().Slice(), " "))
+			}
+			if cliContext.Bool("host") {
+				utils.UseHostRuntime()
+			}
+			return nil
+		},
 		Commands: []*cli.Command{
 			{
 				Name:        "db",
@@ -151,9 +580,15 @@ func main() {
 			{
 				Name:        "env",
 				Usage:       "Environment management",
-				Subcommands: []*cli.Command{envSqliteCmd, envMailtrapCmd, envMaildevCmd, envSyncCmd},
+				Subcommands: []*cli.Command{envDBCmd, envSqliteCmd, envMailtrapCmd, envMaildevCmd, envSyncCmd, envEncryptCmd, envDecryptCmd, envKeyCmd, envScanCmd, envExampleCmd, envHistoryCmd, envUndoCmd, envFromRemoteCmd, envLintCmd, envFmtCmd, envTestingCmd, envGuardCmd},
 			},
 			// Top-level commands with colon notation (reuse command definitions)
+			{
+				Name:      "env:db",
+				Usage:     envDBCmd.Usage,
+				ArgsUsage: envDBCmd.ArgsUsage,
+				Action:    envDBCmd.Action,
+			},
 			{
 				Name:   "env:sqlite",
 				Usage:  envSqliteCmd.Usage,
@@ -173,8 +608,102 @@ func main() {
 				Name:        "env:sync",
 				Aliases:     []string{"sync:env"},
 				Usage:       envSyncCmd.Usage,
-				Description: "Sync the .env file with .env.example",
+				ArgsUsage:   envSyncCmd.ArgsUsage,
+				Description: "Sync the .env file with .env.example, or reconcile keys into several targets configured in .mo.json",
 				Action:      envSyncCmd.Action,
+				Flags:       envSyncCmd.Flags,
+			},
+			{
+				Name:   "env:encrypt",
+				Usage:  envEncryptCmd.Usage,
+				Action: envEncryptCmd.Action,
+				Flags:  envEncryptCmd.Flags,
+			},
+			{
+				Name:   "env:decrypt",
+				Usage:  envDecryptCmd.Usage,
+				Action: envDecryptCmd.Action,
+				Flags:  envDecryptCmd.Flags,
+			},
+			{
+				Name:   "env:key",
+				Usage:  envKeyCmd.Usage,
+				Action: envKeyCmd.Action,
+				Flags:  envKeyCmd.Flags,
+			},
+			{
+				Name:   "env:scan",
+				Usage:  envScanCmd.Usage,
+				Action: envScanCmd.Action,
+				Flags:  envScanCmd.Flags,
+			},
+			{
+				Name:   "env:example",
+				Usage:  envExampleCmd.Usage,
+				Action: envExampleCmd.Action,
+				Flags:  envExampleCmd.Flags,
+			},
+			{
+				Name:   "env:history",
+				Usage:  envHistoryCmd.Usage,
+				Action: envHistoryCmd.Action,
+				Flags:  envHistoryCmd.Flags,
+			},
+			{
+				Name:      "env:undo",
+				Usage:     envUndoCmd.Usage,
+				ArgsUsage: envUndoCmd.ArgsUsage,
+				Action:    envUndoCmd.Action,
+			},
+			{
+				Name:      "env:from-remote",
+				Usage:     envFromRemoteCmd.Usage,
+				ArgsUsage: envFromRemoteCmd.ArgsUsage,
+				Action:    envFromRemoteCmd.Action,
+				Flags:     envFromRemoteCmd.Flags,
+			},
+			{
+				Name:      "env:lint",
+				Usage:     envLintCmd.Usage,
+				ArgsUsage: envLintCmd.ArgsUsage,
+				Action:    envLintCmd.Action,
+			},
+			{
+				Name:      "env:fmt",
+				Usage:     envFmtCmd.Usage,
+				ArgsUsage: envFmtCmd.ArgsUsage,
+				Action:    envFmtCmd.Action,
+			},
+			{
+				Name:   "env:testing",
+				Usage:  envTestingCmd.Usage,
+				Action: envTestingCmd.Action,
+				Flags:  envTestingCmd.Flags,
+			},
+			{
+				Name:   "env:guard",
+				Usage:  envGuardCmd.Usage,
+				Action: envGuardCmd.Action,
+				Flags:  envGuardCmd.Flags,
+			},
+			{
+				Name:        "mail",
+				Usage:       "Mail helpers",
+				Subcommands: []*cli.Command{mailTestCmd, mailCatchCmd},
+			},
+			// Top-level commands with colon notation (reuse command definitions)
+			{
+				Name:      "mail:test",
+				Usage:     mailTestCmd.Usage,
+				ArgsUsage: mailTestCmd.ArgsUsage,
+				Action:    mailTestCmd.Action,
+				Flags:     mailTestCmd.Flags,
+			},
+			{
+				Name:   "mail:catch",
+				Usage:  mailCatchCmd.Usage,
+				Action: mailCatchCmd.Action,
+				Flags:  mailCatchCmd.Flags,
 			},
 			{
 				Name:    "config:edit",
@@ -207,6 +736,18 @@ func main() {
 				Usage:   "Setup a project by running appropriate commands",
 				Action:  commands.CheckProject,
 			},
+			{
+				Name:      "dev",
+				Usage:     "Run the dev processes (serve, queue, schedule, vite) together",
+				ArgsUsage: "[names...]",
+				Action:    commands.Dev,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "no-restart",
+						Usage: "Don't restart processes that exit",
+					},
+				},
+			},
 			{
 				Name:   "pull",
 				Usage:  "Pull storage or database from a remote server",
@@ -220,6 +761,10 @@ func main() {
 						Name:  "database",
 						Usage: "Pull the database",
 					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Import the database even if APP_ENV or DB_HOST are not local",
+					},
 				},
 			},
 			{
@@ -240,7 +785,7 @@ func main() {
 			{
 				Name:        "l",
 				Usage:       "Laravel specific commands",
-				Subcommands: []*cli.Command{laravelClearCmd, laravelFreshCmd},
+				Subcommands: []*cli.Command{laravelClearCmd, laravelCacheCmd, laravelFreshCmd, laravelLogsCmd, laravelRoutesCmd, laravelDoctorCmd, laravelMigrationsCmd, laravelFailedCmd},
 			},
 			// Top-level commands with colon notation (reuse command definitions)
 			{
@@ -248,6 +793,13 @@ func main() {
 				Aliases: []string{"lc"},
 				Usage:   laravelClearCmd.Usage,
 				Action:  laravelClearCmd.Action,
+				Flags:   laravelClearCmd.Flags,
+			},
+			{
+				Name:   "l:cache",
+				Usage:  laravelCacheCmd.Usage,
+				Action: laravelCacheCmd.Action,
+				Flags:  laravelCacheCmd.Flags,
 			},
 			{
 				Name:    "l:fresh",
@@ -256,6 +808,38 @@ func main() {
 				Action:  laravelFreshCmd.Action,
 				Flags:   laravelFreshCmd.Flags,
 			},
+			{
+				Name:      "l:logs",
+				Usage:     laravelLogsCmd.Usage,
+				ArgsUsage: laravelLogsCmd.ArgsUsage,
+				Action:    laravelLogsCmd.Action,
+				Flags:     laravelLogsCmd.Flags,
+			},
+			{
+				Name:      "l:routes",
+				Usage:     laravelRoutesCmd.Usage,
+				ArgsUsage: laravelRoutesCmd.ArgsUsage,
+				Action:    laravelRoutesCmd.Action,
+				Flags:     laravelRoutesCmd.Flags,
+			},
+			{
+				Name:   "l:doctor",
+				Usage:  laravelDoctorCmd.Usage,
+				Action: laravelDoctorCmd.Action,
+			},
+			{
+				Name:   "l:migrations",
+				Usage:  laravelMigrationsCmd.Usage,
+				Action: laravelMigrationsCmd.Action,
+				Flags:  laravelMigrationsCmd.Flags,
+			},
+			{
+				Name:        "l:failed",
+				Usage:       laravelFailedCmd.Usage,
+				Action:      laravelFailedCmd.Action,
+				Flags:       laravelFailedCmd.Flags,
+				Subcommands: laravelFailedCmd.Subcommands,
+			},
 			// Top-level commands with colon notation (reuse command definitions)
 			{
 				Name:    "ssh:add",
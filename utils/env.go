@@ -35,11 +35,19 @@ func (e *EnvManager) GetVar(key string) (string, bool, error) {
 }
 
 func (e *EnvManager) SetVar(key, value string) error {
+	newLine := fmt.Sprintf("%s=%s", key, value)
+	change := EnvJournalEntry{File: e.Path, Key: key, NewValue: value, NewLine: newLine}
+
 	data, err := os.ReadFile(e.Path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			line := fmt.Sprintf("%s=%s\n", key, value)
-			return os.WriteFile(e.Path, []byte(line), 0644)
+			if err := os.WriteFile(e.Path, []byte(newLine+"\n"), 0644); err != nil {
+				return err
+			}
+			change.Added = true
+			change.Created = true
+			recordEnvChange(change)
+			return nil
 		}
 		return err
 	}
@@ -60,18 +68,106 @@ func (e *EnvManager) SetVar(key, value string) error {
 
 		parts := strings.SplitN(content, "=", 2)
 		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
-			lines[i] = fmt.Sprintf("%s=%s", key, value) // overwrite and uncomment
+			change.OldValue = strings.TrimSpace(parts[1])
+			change.OldLine = line
+			change.LineNumber = i
+			lines[i] = newLine // overwrite and uncomment
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
+		change.Added = true
+		change.LineNumber = len(lines)
+		lines = append(lines, newLine)
+	}
+
+	if found && change.OldLine == newLine {
+		return nil
 	}
 
 	content := strings.Join(lines, "\n")
-	return os.WriteFile(e.Path, []byte(content), 0644)
+	if err := os.WriteFile(e.Path, []byte(content), 0644); err != nil {
+		return err
+	}
+	recordEnvChange(change)
+	return nil
+}
+
+// GetCommentedVar returns the value of a key that is present but commented out (# KEY=value)
+func (e *EnvManager) GetCommentedVar(key string) (string, bool, error) {
+	data, err := os.ReadFile(e.Path)
+	if err != nil {
+		return "", false, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSpace(line[1:]), "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			return strings.TrimSpace(parts[1]), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// CommentVar comments out an active key so its value is kept but no longer used
+func (e *EnvManager) CommentVar(key string) error {
+	data, err := os.ReadFile(e.Path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	var changes []EnvJournalEntry
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		parts := strings.SplitN(trimmed, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			lines[i] = "# " + trimmed
+			changes = append(changes, EnvJournalEntry{
+				File:       e.Path,
+				Key:        key,
+				OldValue:   strings.TrimSpace(parts[1]),
+				NewValue:   strings.TrimSpace(parts[1]),
+				OldLine:    line,
+				NewLine:    lines[i],
+				LineNumber: i,
+			})
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := os.WriteFile(e.Path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		recordEnvChange(change)
+	}
+	return nil
+}
+
+// UnquoteValue strips surrounding quotes from a raw .env value and treats
+// Laravel's "null" placeholder as an empty value
+func UnquoteValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	if strings.EqualFold(value, "null") || strings.EqualFold(value, "(null)") {
+		return ""
+	}
+	return value
 }
 
 func EnsureRequiredEnvVars(context string) (map[string]string, error) {
@@ -134,3 +134,61 @@ func TestEnvManager_GetVar_NonExistentFile(t *testing.T) {
 		t.Error("Expected error for non-existent file, got nil")
 	}
 }
+
+func TestUnquoteValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`"hello world"`, "hello world"},
+		{`'single'`, "single"},
+		{"plain", "plain"},
+		{"null", ""},
+		{`"mismatched'`, `"mismatched'`},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := UnquoteValue(tt.value); got != tt.want {
+				t.Errorf("UnquoteValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvManager_CommentVar(t *testing.T) {
+	tmpDir := t.TempDir()
+	envPath := filepath.Join(tmpDir, ".env")
+
+	content := "DB_CONNECTION=mysql\nDB_HOST=127.0.0.1\nDB_PORT=3306\n"
+	if err := os.WriteFile(envPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewEnvManager(envPath)
+	if err := manager.CommentVar("DB_HOST"); err != nil {
+		t.Fatalf("CommentVar() error = %v", err)
+	}
+
+	if _, found, _ := manager.GetVar("DB_HOST"); found {
+		t.Error("DB_HOST still active after CommentVar()")
+	}
+
+	value, found, err := manager.GetCommentedVar("DB_HOST")
+	if err != nil {
+		t.Fatalf("GetCommentedVar() error = %v", err)
+	}
+	if !found || value != "127.0.0.1" {
+		t.Errorf("GetCommentedVar() = %v, %v, want 127.0.0.1, true", value, found)
+	}
+
+	// SetVar uncomments the key again
+	if err := manager.SetVar("DB_HOST", value); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(envPath)
+	if string(data) != content {
+		t.Errorf("content after round trip = %q, want %q", string(data), content)
+	}
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCipher is the cipher Laravel uses when config/app.php doesn't set one
+const DefaultCipher = "AES-256-CBC"
+
+// encryptedPayload mirrors the JSON structure Laravel's Encrypter produces.
+// Field order matters so the output matches artisan byte for byte.
+type encryptedPayload struct {
+	IV    string `json:"iv"`
+	Value string `json:"value"`
+	Mac   string `json:"mac"`
+	Tag   string `json:"tag"`
+}
+
+// EnvFilePath returns the env file for the given environment (".env" or ".env.<environment>")
+func EnvFilePath(environment string) string {
+	if environment == "" {
+		return ".env"
+	}
+	return ".env." + environment
+}
+
+// CipherKeyLength returns the key size in bytes for a cipher Laravel supports
+func CipherKeyLength(cipherName string) (int, error) {
+	switch strings.ToLower(cipherName) {
+	case "aes-128-cbc", "aes-128-gcm":
+		return 16, nil
+	case "aes-256-cbc", "aes-256-gcm":
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("unsupported cipher: %s (supported: AES-128-CBC, AES-256-CBC, AES-128-GCM, AES-256-GCM)", cipherName)
+	}
+}
+
+// GenerateKey returns a random key for the cipher in Laravel's "base64:" notation
+func GenerateKey(cipherName string) (string, error) {
+	length, err := CipherKeyLength(cipherName)
+	if err != nil {
+		return "", err
+	}
+
+	key := make([]byte, length)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("error generating key: %w", err)
+	}
+
+	return "base64:" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a key given either as "base64:..." or as the raw key string
+func ParseKey(key string) ([]byte, error) {
+	if strings.HasPrefix(key, "base64:") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key, "base64:"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 key: %w", err)
+		}
+		return decoded, nil
+	}
+	return []byte(key), nil
+}
+
+// EncryptEnv encrypts the contents of an env file the same way
+// `php artisan env:encrypt` does (serialized string, AES-CBC, HMAC-SHA256)
+func EncryptEnv(plaintext, key []byte, cipherName string) ([]byte, error) {
+	if err := validateKey(key, cipherName); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("error generating iv: %w", err)
+	}
+
+	data := pkcs7Pad(phpSerializeString(plaintext), aes.BlockSize)
+	encrypted := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, data)
+
+	payload := encryptedPayload{
+		IV:    base64.StdEncoding.EncodeToString(iv),
+		Value: base64.StdEncoding.EncodeToString(encrypted),
+	}
+	payload.Mac = computeMac(payload.IV, payload.Value, key)
+
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(jsonBytes)), nil
+}
+
+// DecryptEnv reverses EncryptEnv and also reads files written by artisan
+func DecryptEnv(encrypted, key []byte, cipherName string) ([]byte, error) {
+	if err := validateKey(key, cipherName); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encrypted)))
+	if err != nil {
+		return nil, fmt.Errorf("the payload is invalid: %w", err)
+	}
+
+	var payload encryptedPayload
+	if err := json.Unmarshal(jsonBytes, &payload); err != nil {
+		return nil, fmt.Errorf("the payload is invalid: %w", err)
+	}
+
+	expectedMac := computeMac(payload.IV, payload.Value, key)
+	if !hmac.Equal([]byte(expectedMac), []byte(payload.Mac)) {
+		return nil, fmt.Errorf("the MAC is invalid")
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(payload.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("the payload is invalid: bad iv")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload.Value)
+	if err != nil || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("the payload is invalid: bad value")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	decrypted, err = pkcs7Unpad(decrypted, aes.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt the data: %w", err)
+	}
+
+	return phpUnserializeString(decrypted)
+}
+
+func validateKey(key []byte, cipherName string) error {
+	if !strings.HasSuffix(strings.ToLower(cipherName), "-cbc") {
+		return fmt.Errorf("unsupported cipher for env encryption: %s (use AES-128-CBC or AES-256-CBC)", cipherName)
+	}
+	length, err := CipherKeyLength(cipherName)
+	if err != nil {
+		return err
+	}
+	if len(key) != length {
+		return fmt.Errorf("unsupported key length %d for %s (expected %d bytes)", len(key), cipherName, length)
+	}
+	return nil
+}
+
+func computeMac(iv, value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(iv + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("invalid padding")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
+
+// phpSerializeString produces serialize($string) output: s:<len>:"<value>";
+func phpSerializeString(value []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "s:%d:\"", len(value))
+	buf.Write(value)
+	buf.WriteString("\";")
+	return buf.Bytes()
+}
+
+func phpUnserializeString(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte("s:")) {
+		return nil, fmt.Errorf("unexpected serialized payload")
+	}
+	rest := data[2:]
+	sep := bytes.IndexByte(rest, ':')
+	if sep < 0 {
+		return nil, fmt.Errorf("unexpected serialized payload")
+	}
+	length, err := strconv.Atoi(string(rest[:sep]))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected serialized payload: %w", err)
+	}
+	rest = rest[sep+1:]
+	if len(rest) != length+3 || rest[0] != '"' || string(rest[length+1:]) != "\";" {
+		return nil, fmt.Errorf("unexpected serialized payload")
+	}
+	return rest[1 : length+1], nil
+}
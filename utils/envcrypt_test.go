@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptEnv_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cipher string
+	}{
+		{"aes-256-cbc", "AES-256-CBC"},
+		{"aes-128-cbc", "AES-128-CBC"},
+	}
+
+	plaintext := []byte("APP_NAME=mo\nAPP_KEY=base64:abc/def+ghi=\n# comment\nDB_PASSWORD=\"s3cr3t\"\n")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := GenerateKey(tt.cipher)
+			if err != nil {
+				t.Fatalf("GenerateKey() error = %v", err)
+			}
+			rawKey, err := ParseKey(key)
+			if err != nil {
+				t.Fatalf("ParseKey() error = %v", err)
+			}
+
+			encrypted, err := EncryptEnv(plaintext, rawKey, tt.cipher)
+			if err != nil {
+				t.Fatalf("EncryptEnv() error = %v", err)
+			}
+
+			decrypted, err := DecryptEnv(encrypted, rawKey, tt.cipher)
+			if err != nil {
+				t.Fatalf("DecryptEnv() error = %v", err)
+			}
+			if string(decrypted) != string(plaintext) {
+				t.Errorf("DecryptEnv() = %q, want %q", decrypted, plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptEnv_LaravelPayload(t *testing.T) {
+	// Produced with openssl using Laravel's payload layout (serialized string)
+	payload := "eyJpdiI6IlptVmtZMkpoT1RnM05qVTBNekl4TUE9PSIsInZhbHVlIjoiNnNuVkJZc3kzZHRyd1VVQytUa29MMHVRQ1NtaUJIT0xlUGorT0p0cW9Gdz0iLCJtYWMiOiI1YTI2NThjOTMxMmY2MTFjZGJjNDJkNWJhMWViM2VjMjQzMzMwMDk5YTFiYTM2ODU3N2Q3OGQyYjBhNzk1OGFjIiwidGFnIjoiIn0="
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	decrypted, err := DecryptEnv([]byte(payload), key, DefaultCipher)
+	if err != nil {
+		t.Fatalf("DecryptEnv() error = %v", err)
+	}
+	if string(decrypted) != "APP_NAME=mo\n" {
+		t.Errorf("DecryptEnv() = %q, want %q", decrypted, "APP_NAME=mo\n")
+	}
+}
+
+func TestEncryptEnv_UnescapedSlashes(t *testing.T) {
+	// Laravel encodes the payload with JSON_UNESCAPED_SLASHES
+	key := []byte("0123456789abcdef0123456789abcdef")
+	for i := 0; i < 20; i++ {
+		encrypted, err := EncryptEnv([]byte("APP_KEY=base64:abc/def\n"), key, DefaultCipher)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonBytes, err := base64.StdEncoding.DecodeString(string(encrypted))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(jsonBytes), `\/`) {
+			t.Fatalf("payload escapes slashes: %s", jsonBytes)
+		}
+	}
+}
+
+func TestDecryptEnv_WrongKey(t *testing.T) {
+	key, _ := GenerateKey(DefaultCipher)
+	rawKey, _ := ParseKey(key)
+	encrypted, err := EncryptEnv([]byte("APP_ENV=local\n"), rawKey, DefaultCipher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, _ := GenerateKey(DefaultCipher)
+	otherRawKey, _ := ParseKey(otherKey)
+	_, err = DecryptEnv(encrypted, otherRawKey, DefaultCipher)
+	if err == nil || !strings.Contains(err.Error(), "MAC is invalid") {
+		t.Errorf("DecryptEnv() error = %v, want MAC is invalid", err)
+	}
+}
+
+func TestEnvFilePath(t *testing.T) {
+	if got := EnvFilePath(""); got != ".env" {
+		t.Errorf("EnvFilePath(\"\") = %v, want .env", got)
+	}
+	if got := EnvFilePath("staging"); got != ".env.staging" {
+		t.Errorf("EnvFilePath(\"staging\") = %v, want .env.staging", got)
+	}
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mo/config"
+)
+
+// EnvJournalEntry records a single line change mo made to an env file
+type EnvJournalEntry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	File       string    `json:"file"`
+	Key        string    `json:"key"`
+	OldValue   string    `json:"old_value"`
+	NewValue   string    `json:"new_value"`
+	OldLine    string    `json:"old_line"`
+	NewLine    string    `json:"new_line"`
+	LineNumber int       `json:"line_number"`
+	Added      bool      `json:"added"`
+	Created    bool      `json:"created,omitempty"`
+}
+
+// envJournalCommand is the command recorded with each entry. Journaling is
+// disabled while it is empty, so only the CLI (not tests) writes history.
+var envJournalCommand string
+
+// envJournalPathFunc returns the journal file, overridable for testing
+var envJournalPathFunc = defaultEnvJournalPath
+
+// EnableEnvJournal turns on journaling of .env changes for the given command
+func EnableEnvJournal(command string) {
+	envJournalCommand = command
+}
+
+func defaultEnvJournalPath() (string, error) {
+	dir, err := config.ProjectDataDir(".")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "env-history.jsonl"), nil
+}
+
+func recordEnvChange(entry EnvJournalEntry) {
+	if envJournalCommand == "" {
+		return
+	}
+
+	entry.Time = time.Now()
+	entry.Command = envJournalCommand
+	if absPath, err := filepath.Abs(entry.File); err == nil {
+		entry.File = absPath
+	}
+
+	if err := appendEnvJournal(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record .env change in history: %v\n", err)
+	}
+}
+
+func appendEnvJournal(entry EnvJournalEntry) error {
+	journalPath, err := envJournalPathFunc()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(journalPath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// ReadEnvJournal returns all recorded changes, oldest first
+func ReadEnvJournal() ([]EnvJournalEntry, error) {
+	journalPath, err := envJournalPathFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(journalPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []EnvJournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry EnvJournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("error parsing history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func writeEnvJournal(entries []EnvJournalEntry) error {
+	journalPath, err := envJournalPathFunc()
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		builder.Write(data)
+		builder.WriteByte('\n')
+	}
+
+	return os.WriteFile(journalPath, []byte(builder.String()), 0600)
+}
+
+// UndoEnvChanges rolls back the last n recorded changes, newest first, and
+// removes them from the journal. It returns the entries that were undone.
+func UndoEnvChanges(n int) ([]EnvJournalEntry, error) {
+	entries, err := ReadEnvJournal()
+	if err != nil {
+		return nil, err
+	}
+	if n > len(entries) {
+		n = len(entries)
+	}
+
+	var undone []EnvJournalEntry
+	for i := len(entries) - 1; i >= len(entries)-n; i-- {
+		if err := revertEnvChange(entries[i]); err != nil {
+			if writeErr := writeEnvJournal(entries[:i+1]); writeErr != nil {
+				return undone, writeErr
+			}
+			return undone, fmt.Errorf("cannot undo %s in %s: %w", entries[i].Key, entries[i].File, err)
+		}
+		undone = append(undone, entries[i])
+	}
+
+	return undone, writeEnvJournal(entries[:len(entries)-n])
+}
+
+func revertEnvChange(entry EnvJournalEntry) error {
+	data, err := os.ReadFile(entry.File)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+
+	// Prefer the recorded position, fall back to searching in case lines moved
+	index := -1
+	if entry.LineNumber >= 0 && entry.LineNumber < len(lines) && lines[entry.LineNumber] == entry.NewLine {
+		index = entry.LineNumber
+	} else {
+		for i, line := range lines {
+			if line == entry.NewLine {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("the line %q was changed since", entry.NewLine)
+	}
+
+	if entry.Added {
+		lines = append(lines[:index], lines[index+1:]...)
+	} else {
+		lines[index] = entry.OldLine
+	}
+
+	content := strings.Join(lines, "\n")
+	if entry.Created && strings.TrimSpace(content) == "" {
+		return os.Remove(entry.File)
+	}
+	return os.WriteFile(entry.File, []byte(content), 0644)
+}
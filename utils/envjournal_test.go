@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvJournal_Undo(t *testing.T) {
+	tmpDir := t.TempDir()
+	envPath := filepath.Join(tmpDir, ".env")
+
+	originalPathFunc := envJournalPathFunc
+	envJournalPathFunc = func() (string, error) {
+		return filepath.Join(tmpDir, "history", "env-history.jsonl"), nil
+	}
+	EnableEnvJournal("test")
+	defer func() {
+		envJournalPathFunc = originalPathFunc
+		EnableEnvJournal("")
+	}()
+
+	content := "APP_ENV=local\n# MAIL_HOST=smtp.example.com\nDB_HOST=127.0.0.1\n"
+	if err := os.WriteFile(envPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewEnvManager(envPath)
+	if err := manager.SetVar("APP_ENV", "testing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetVar("MAIL_HOST", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetVar("NEW_KEY", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CommentVar("DB_HOST"); err != nil {
+		t.Fatal(err)
+	}
+	// Setting an unchanged value is not recorded
+	if err := manager.SetVar("APP_ENV", "testing"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadEnvJournal()
+	if err != nil {
+		t.Fatalf("ReadEnvJournal() error = %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("ReadEnvJournal() returned %d entries, want 4", len(entries))
+	}
+	if entries[0].Key != "APP_ENV" || entries[0].OldValue != "local" || entries[0].NewValue != "testing" || entries[0].Command != "test" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+
+	undone, err := UndoEnvChanges(2)
+	if err != nil {
+		t.Fatalf("UndoEnvChanges() error = %v", err)
+	}
+	if len(undone) != 2 || undone[0].Key != "DB_HOST" || undone[1].Key != "NEW_KEY" {
+		t.Errorf("UndoEnvChanges() undid %+v", undone)
+	}
+
+	if _, err := UndoEnvChanges(10); err != nil {
+		t.Fatalf("UndoEnvChanges() error = %v", err)
+	}
+
+	data, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("content after undo = %q, want %q", string(data), content)
+	}
+
+	entries, _ = ReadEnvJournal()
+	if len(entries) != 0 {
+		t.Errorf("journal should be empty after undoing everything, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// ProcessGroupCommand returns a command that runs in its own process group, so
+// it and everything it spawns can be signalled together
+func ProcessGroupCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// SignalProcessGroup sends sig to the process group of a started command
+func SignalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	signal, ok := sig.(syscall.Signal)
+	if !ok {
+		return cmd.Process.Signal(sig)
+	}
+	return syscall.Kill(-cmd.Process.Pid, signal)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+)
+
+// ProcessGroupCommand returns a plain command, Windows has no process groups to signal
+func ProcessGroupCommand(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
+
+// SignalProcessGroup kills the process, Windows can't deliver other signals
+func SignalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
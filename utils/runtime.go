@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mo/config"
+)
+
+// Runtime is where a project's php, composer, npm and mysql commands run
+type Runtime string
+
+const (
+	RuntimeHost    Runtime = "host"
+	RuntimeSail    Runtime = "sail"
+	RuntimeCompose Runtime = "compose"
+	RuntimeDDEV    Runtime = "ddev"
+)
+
+// composeFiles are the file names docker compose looks for, in its own order
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+var laravelServicePattern = regexp.MustCompile(`(?m)^\s+['"]?laravel\.test['"]?:\s*$`)
+
+var unsafeShellPattern = regexp.MustCompile(`[^A-Za-z0-9_./:=,@%+-]`)
+
+var databaseServicePattern = regexp.MustCompile(`(?m)^\s+['"]?(mysql|mariadb)['"]?:\s*$`)
+
+// appTools run in the application container, databaseTools in the database container
+var appTools = []string{"php", "composer", "npm", "npx", "node", "pnpm", "yarn", "bun"}
+var databaseTools = []string{"mysql", "mysqldump"}
+
+// useHostRuntime is set by --host to skip container detection
+var useHostRuntime bool
+
+// UseHostRuntime makes all project commands run on the host
+func UseHostRuntime() {
+	useHostRuntime = true
+}
+
+// ProjectRuntime returns the runtime of the project in the current directory.
+// --host wins over "runtime" in .mo.json, which wins over the global config.
+// Without any of them the runtime is detected.
+func ProjectRuntime() (Runtime, error) {
+	if useHostRuntime {
+		return RuntimeHost, nil
+	}
+
+	projectConfig, err := config.LoadProjectConfig(".")
+	if err != nil {
+		return "", err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading config: %w", err)
+	}
+
+	for _, configured := range []string{projectConfig.Runtime, cfg.Runtime} {
+		switch Runtime(configured) {
+		case "", "auto":
+			continue
+		case RuntimeHost, RuntimeSail, RuntimeCompose, RuntimeDDEV:
+			return Runtime(configured), nil
+		default:
+			return "", fmt.Errorf("unknown runtime %q (supported: auto, host, sail, compose, ddev)", configured)
+		}
+	}
+
+	return DetectRuntime("."), nil
+}
+
+// DetectRuntime looks for DDEV or a docker compose file with a laravel.test
+// service in dir. laravel/sail is installed in every new Laravel app, so Sail
+// is only used when its compose file is there too.
+func DetectRuntime(dir string) Runtime {
+	if _, err := os.Stat(filepath.Join(dir, ".ddev", "config.yaml")); err == nil {
+		return RuntimeDDEV
+	}
+	if content := composeFileContent(dir); laravelServicePattern.MatchString(content) {
+		if _, err := os.Stat(filepath.Join(dir, "vendor", "bin", "sail")); err == nil {
+			return RuntimeSail
+		}
+		return RuntimeCompose
+	}
+	return RuntimeHost
+}
+
+func composeFileContent(dir string) string {
+	for _, name := range composeFiles {
+		if content, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return string(content)
+		}
+	}
+	return ""
+}
+
+// databaseService returns the compose service running MySQL or MariaDB
+func databaseService(dir string) string {
+	if match := databaseServicePattern.FindStringSubmatch(composeFileContent(dir)); match != nil {
+		return match[1]
+	}
+	return "mysql"
+}
+
+// ContainerCommand rewrites a command to run inside the runtime's containers.
+// Commands other than php, composer, the Node.js tools, mysql and mysqldump
+// are returned unchanged. env is passed into the container with -e (or env
+// for DDEV), on the host the caller sets it on the process. Without tty app
+// tools run with exec -T like the database tools, a pseudo-terminal fails
+// under docker-compose and adds \r to captured output.
+func ContainerCommand(runtime Runtime, env map[string]string, tty bool, name string, args ...string) (string, []string) {
+	isAppTool := contains(appTools, name)
+	isDatabaseTool := contains(databaseTools, name)
+	if runtime == RuntimeHost || (!isAppTool && !isDatabaseTool) {
+		return name, args
+	}
+
+	var envFlags []string
+	for _, pair := range EnvPairs(env) {
+		envFlags = append(envFlags, "-e", pair)
+	}
+
+	appExec := []string{"exec"}
+	if !tty {
+		appExec = append(appExec, "-T")
+	}
+
+	switch runtime {
+	case RuntimeSail:
+		if isAppTool {
+			if len(env) == 0 && tty {
+				return "vendor/bin/sail", append([]string{name}, args...)
+			}
+			// The sail shortcuts can't pass env or -T, exec as the sail user like they do
+			return "vendor/bin/sail", append(append(append(append(appExec, "-u", "sail"), envFlags...), "laravel.test", name), args...)
+		}
+		// Sail passes unknown commands on to docker compose
+		return "vendor/bin/sail", append(append(append([]string{"exec", "-T"}, envFlags...), databaseService("."), name), args...)
+	case RuntimeCompose:
+		composeCmd, composeArgs := dockerCompose()
+		if isAppTool {
+			return composeCmd, append(append(append(append(composeArgs, appExec...), envFlags...), "laravel.test", name), args...)
+		}
+		return composeCmd, append(append(append(append(composeArgs, "exec", "-T"), envFlags...), databaseService("."), name), args...)
+	case RuntimeDDEV:
+		// ddev exec has no -e, env sets the variables inside the container
+		command := append([]string{name}, args...)
+		if len(env) > 0 {
+			command = append(append([]string{"env"}, EnvPairs(env)...), command...)
+		}
+		if isAppTool {
+			return "ddev", append([]string{"exec"}, command...)
+		}
+		return "ddev", append([]string{"exec", "-s", "db"}, command...)
+	}
+
+	return name, args
+}
+
+// AppContainerCommand rewrites any command to run in the application
+// container, as root. ContainerCommand only moves the known tools.
+func AppContainerCommand(runtime Runtime, name string, args ...string) (string, []string) {
+	switch runtime {
+	case RuntimeSail:
+		return "vendor/bin/sail", append([]string{"exec", "-T", "laravel.test", name}, args...)
+	case RuntimeCompose:
+		composeCmd, composeArgs := dockerCompose()
+		return composeCmd, append(append(composeArgs, "exec", "-T", "laravel.test", name), args...)
+	case RuntimeDDEV:
+		// ddev exec joins its arguments into a bash command line
+		quoted := []string{"exec", shellQuote(name)}
+		for _, arg := range args {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		return "ddev", quoted
+	}
+	return name, args
+}
+
+// shellQuote single quotes s for sh unless it only has safe characters
+func shellQuote(s string) string {
+	if s != "" && !unsafeShellPattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// EnvPairs returns env as sorted KEY=value pairs, the format of exec.Cmd.Env
+func EnvPairs(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + env[key]
+	}
+	return pairs
+}
+
+// dockerCompose prefers the compose plugin and falls back to docker-compose
+func dockerCompose() (string, []string) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		if _, err := exec.LookPath("docker-compose"); err == nil {
+			return "docker-compose", nil
+		}
+	}
+	return "docker", []string{"compose"}
+}
+
+// ProjectCommand returns a command that runs in the project's runtime, wired
+// to the terminal. Callers may replace Stdin to pipe data in, use
+// CapturedProjectCommand to read the output.
+func ProjectCommand(name string, args ...string) (*exec.Cmd, error) {
+	return ProjectCommandWithEnv(nil, name, args...)
+}
+
+// ProjectCommandWithEnv is ProjectCommand with extra environment variables,
+// which also reach commands running in a container
+func ProjectCommandWithEnv(env map[string]string, name string, args ...string) (*exec.Cmd, error) {
+	return projectCommand(env, isTerminal(os.Stdin) && isTerminal(os.Stdout), name, args...)
+}
+
+// CapturedProjectCommand returns a command whose output the caller reads by
+// setting Stdout. It never gets a terminal in the container and has no stdin.
+func CapturedProjectCommand(name string, args ...string) (*exec.Cmd, error) {
+	cmd, err := projectCommand(nil, false, name, args...)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	return cmd, nil
+}
+
+func projectCommand(env map[string]string, tty bool, name string, args ...string) (*exec.Cmd, error) {
+	runtime, err := ProjectRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	name, args = ContainerCommand(runtime, env, tty, name, args...)
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), EnvPairs(env)...)
+	}
+	return cmd, nil
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// RunProjectCommand runs php, composer, npm or mysql in the project's runtime
+func RunProjectCommand(name string, args ...string) error {
+	cmd, err := ProjectCommand(name, args...)
+	if err != nil {
+		return err
+	}
+	return cmd.Run()
+}
+
+func contains(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
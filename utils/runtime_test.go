@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectRuntime(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Runtime
+	}{
+		{
+			name: "plain project",
+			files: map[string]string{
+				"artisan": "",
+			},
+			want: RuntimeHost,
+		},
+		{
+			name: "sail",
+			files: map[string]string{
+				"vendor/bin/sail":    "",
+				"docker-compose.yml": "services:\n    laravel.test:\n        image: sail-8.3/app\n",
+			},
+			want: RuntimeSail,
+		},
+		{
+			name: "sail installed without its compose file",
+			files: map[string]string{
+				"artisan":         "",
+				"vendor/bin/sail": "",
+			},
+			want: RuntimeHost,
+		},
+		{
+			name: "compose with laravel.test",
+			files: map[string]string{
+				"compose.yaml": "services:\n  'laravel.test':\n    build: .\n  mariadb:\n    image: mariadb\n",
+			},
+			want: RuntimeCompose,
+		},
+		{
+			name: "compose without laravel.test",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  redis:\n    image: redis\n",
+			},
+			want: RuntimeHost,
+		},
+		{
+			name: "ddev",
+			files: map[string]string{
+				".ddev/config.yaml": "name: shop\n",
+				"vendor/bin/sail":   "",
+			},
+			want: RuntimeDDEV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := DetectRuntime(dir); got != tt.want {
+				t.Errorf("DetectRuntime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerCommand(t *testing.T) {
+	tests := []struct {
+		runtime Runtime
+		name    string
+		args    []string
+		want    string
+	}{
+		{RuntimeHost, "php", []string{"artisan", "migrate"}, "php artisan migrate"},
+		{RuntimeSail, "php", []string{"artisan", "migrate"}, "vendor/bin/sail php artisan migrate"},
+		{RuntimeSail, "mysql", []string{"-u", "root", "shop"}, "vendor/bin/sail exec -T mysql mysql -u root shop"},
+		{RuntimeSail, "git", []string{"status"}, "git status"},
+		{RuntimeDDEV, "npm", []string{"install"}, "ddev exec npm install"},
+		{RuntimeSail, "pnpm", []string{"install", "--frozen-lockfile"}, "vendor/bin/sail pnpm install --frozen-lockfile"},
+		{RuntimeDDEV, "mysqldump", []string{"db"}, "ddev exec -s db mysqldump db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime)+" "+tt.name, func(t *testing.T) {
+			name, args := ContainerCommand(tt.runtime, nil, true, tt.name, tt.args...)
+			if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+				t.Errorf("ContainerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerCommandEnv(t *testing.T) {
+	env := map[string]string{"NODE_ENV": "production", "APP_DEBUG": "false"}
+
+	tests := []struct {
+		runtime Runtime
+		name    string
+		args    []string
+		want    string
+	}{
+		{RuntimeHost, "npm", []string{"run", "build"}, "npm run build"},
+		{RuntimeSail, "npm", []string{"run", "build"}, "vendor/bin/sail exec -u sail -e APP_DEBUG=false -e NODE_ENV=production laravel.test npm run build"},
+		{RuntimeSail, "mysql", []string{"shop"}, "vendor/bin/sail exec -T -e APP_DEBUG=false -e NODE_ENV=production mysql mysql shop"},
+		{RuntimeDDEV, "npm", []string{"run", "build"}, "ddev exec env APP_DEBUG=false NODE_ENV=production npm run build"},
+		{RuntimeDDEV, "mysql", []string{"db"}, "ddev exec -s db env APP_DEBUG=false NODE_ENV=production mysql db"},
+		{RuntimeSail, "git", []string{"status"}, "git status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime)+" "+tt.name, func(t *testing.T) {
+			name, args := ContainerCommand(tt.runtime, env, true, tt.name, tt.args...)
+			if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+				t.Errorf("ContainerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// docker compose or docker-compose, depending on what is installed
+	name, args := ContainerCommand(RuntimeCompose, env, true, "php", "artisan", "migrate")
+	got := strings.Join(append([]string{name}, args...), " ")
+	if !strings.HasSuffix(got, "exec -e APP_DEBUG=false -e NODE_ENV=production laravel.test php artisan migrate") {
+		t.Errorf("ContainerCommand() = %q", got)
+	}
+}
+
+func TestContainerCommandWithoutTTY(t *testing.T) {
+	tests := []struct {
+		runtime Runtime
+		name    string
+		args    []string
+		want    string
+	}{
+		{RuntimeHost, "php", []string{"-m"}, "php -m"},
+		{RuntimeSail, "php", []string{"artisan", "route:list", "--json"}, "vendor/bin/sail exec -T -u sail laravel.test php artisan route:list --json"},
+		{RuntimeSail, "mysqldump", []string{"shop"}, "vendor/bin/sail exec -T mysql mysqldump shop"},
+		{RuntimeDDEV, "php", []string{"-m"}, "ddev exec php -m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime)+" "+tt.name, func(t *testing.T) {
+			name, args := ContainerCommand(tt.runtime, nil, false, tt.name, tt.args...)
+			if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+				t.Errorf("ContainerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// docker compose or docker-compose, depending on what is installed
+	name, args := ContainerCommand(RuntimeCompose, nil, false, "php", "artisan", "migrate:status")
+	got := strings.Join(append([]string{name}, args...), " ")
+	if !strings.HasSuffix(got, "exec -T laravel.test php artisan migrate:status") {
+		t.Errorf("ContainerCommand() = %q", got)
+	}
+}
+
+func TestAppContainerCommand(t *testing.T) {
+	tests := []struct {
+		runtime Runtime
+		args    []string
+		want    string
+	}{
+		{RuntimeHost, []string{"-TERM", "-f", "^php artisan queue:work"}, "pkill -TERM -f ^php artisan queue:work"},
+		{RuntimeSail, []string{"-TERM", "-f", "^php artisan queue:work"}, "vendor/bin/sail exec -T laravel.test pkill -TERM -f ^php artisan queue:work"},
+		{RuntimeDDEV, []string{"-TERM", "-f", "^php artisan queue:work"}, "ddev exec pkill -TERM -f '^php artisan queue:work'"},
+		{RuntimeDDEV, []string{"-f", "it's"}, `ddev exec pkill -f 'it'\''s'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime), func(t *testing.T) {
+			name, args := AppContainerCommand(tt.runtime, "pkill", tt.args...)
+			if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+				t.Errorf("AppContainerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}